@@ -1,9 +1,14 @@
 package config
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Config holds all configuration for the application
 type Config struct {
+	SchemaVersion int `mapstructure:"schema_version"` // e.g., 1, 2
+
 	Server   ServerConfig   `mapstructure:"server"`
 	Database DatabaseConfig `mapstructure:"database"`
 	Redis    RedisConfig    `mapstructure:"redis"`
@@ -11,6 +16,11 @@ type Config struct {
 	JWT      JWTConfig      `mapstructure:"jwt"`
 	Email    EmailConfig    `mapstructure:"email"`
 	App      AppConfig      `mapstructure:"app"`
+	Runtime  RuntimeConfig  `mapstructure:"runtime"`
+
+	// Extensions holds experimental sections from the file's "extensions:" block,
+	// keyed by name and decoded on demand with Manager.UnmarshalExtension
+	Extensions map[string]json.RawMessage `mapstructure:"extensions"`
 }
 
 // ServerConfig holds server configuration
@@ -20,6 +30,22 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration `mapstructure:"read_timeout"`  // e.g., "30s", "1m", "5m"
 	WriteTimeout time.Duration `mapstructure:"write_timeout"` // e.g., "30s", "1m", "5m"
 	IdleTimeout  time.Duration `mapstructure:"idle_timeout"`  // e.g., "60s", "2m", "10m"
+
+	MaxHeaderBytes int `mapstructure:"max_header_bytes"` // e.g., 1048576; 0 uses net/http's default
+
+	// TLS is enabled when both the certificate and key files are set
+	TLSCertFile string `mapstructure:"tls_cert_file"` // e.g., "/etc/app/tls.crt"
+	TLSKeyFile  string `mapstructure:"tls_key_file"`  // e.g., "/etc/app/tls.key"
+
+	MinTLSVersion string `mapstructure:"min_tls_version"` // e.g., "1.2", "1.3"; empty uses crypto/tls's default
+
+	// BindAll acknowledges that binding to all interfaces (0.0.0.0) in
+	// production is intended, silencing the validator's bind-all check
+	BindAll bool `mapstructure:"bind_all"` // e.g., true, false
+
+	// Timeouts holds optional per-operation timeouts keyed by lowercase name,
+	// set from SERVER_TIMEOUT_<NAME> environment variables
+	Timeouts map[string]time.Duration `mapstructure:"timeouts"` // e.g., {"upload": "5m", "report": "90s"}
 }
 
 // DatabaseConfig holds database configuration
@@ -38,6 +64,10 @@ type DatabaseConfig struct {
 	DBReadPassword string `mapstructure:"read_password"` // e.g., "read_password", "replica_password"
 	DBReadName     string `mapstructure:"read_dbname"`   // e.g., "myapp_read", "replica_db"
 
+	// ReadReplicas lists additional read replicas as host:port, sharing the
+	// read database credentials and name
+	ReadReplicas []string `mapstructure:"read_replicas"` // e.g., ["replica-1.internal:5432", "replica-2.internal:5432"]
+
 	// --- Legacy Database Configuration (Backward Compatibility) ---
 	// These fields are used when DATABASE_CONFIG_TYPE=legacy
 	Host     string `mapstructure:"host"`     // e.g., "localhost", "db.example.com", "127.0.0.1"
@@ -52,6 +82,12 @@ type DatabaseConfig struct {
 	DBType             string `mapstructure:"type"`        // e.g., "postgresql", "mysql", "sqlserver", "sqlite"
 	Environment        string `mapstructure:"environment"` // e.g., "development", "staging", "production"
 	DatabaseConfigType string `mapstructure:"config_type"` // e.g., "read_write", "legacy", "auto_detect"
+
+	// --- Connection Pool ---
+	// Zero values fall back to the defaults used by Manager.SQLPoolConfig
+	MaxIdleConns    int           `mapstructure:"max_idle_conns"`     // e.g., 2, 5, 25
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`  // e.g., "30m", "1h"
+	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"` // e.g., "5m", "10m"
 }
 
 // RedisConfig holds Redis configuration
@@ -60,6 +96,11 @@ type RedisConfig struct {
 	Port     string `mapstructure:"port"`     // e.g., "6379", "6380", "26379"
 	Password string `mapstructure:"password"` // e.g., "redis_password", "secret", ""
 	DB       int    `mapstructure:"db"`       // e.g., 0, 1, 2, 15
+	URL      string `mapstructure:"url"`      // e.g., "redis://:password@redis.example.com:6379/2"
+
+	// Connection pool settings; a PoolSize of 0 leaves the client's default
+	PoolSize     int `mapstructure:"pool_size" validate:"min=0"` // e.g., 10, 50
+	MinIdleConns int `mapstructure:"min_idle_conns"`             // e.g., 0, 5
 }
 
 // LogConfig holds logging configuration
@@ -74,6 +115,16 @@ type JWTConfig struct {
 	Secret     string        `mapstructure:"secret"`     // e.g., "your-super-secret-jwt-key-here"
 	Expiration time.Duration `mapstructure:"expiration"` // e.g., "24h", "7d", "30m"
 	Issuer     string        `mapstructure:"issuer"`     // e.g., "myapp", "auth-service", "api-gateway"
+
+	// PreviousSecrets holds rotated-out secrets, newest first, so tokens signed
+	// before a rotation can still be verified
+	PreviousSecrets []string `mapstructure:"previous_secrets"` // e.g., ["old-secret-key-that-is-32-chars-long"]
+
+	// Algorithm selects the signing algorithm. RS* and ES* algorithms sign
+	// with the PEM keys at PrivateKeyPath and PublicKeyPath instead of Secret.
+	Algorithm      string `mapstructure:"algorithm"`        // e.g., "HS256", "RS256", "ES256"
+	PrivateKeyPath string `mapstructure:"private_key_path"` // e.g., "/etc/app/jwt.key"
+	PublicKeyPath  string `mapstructure:"public_key_path"`  // e.g., "/etc/app/jwt.pub"
 }
 
 // EmailConfig holds email configuration
@@ -83,12 +134,30 @@ type EmailConfig struct {
 	Username string `mapstructure:"username"` // e.g., "user@example.com", "noreply@myapp.com"
 	Password string `mapstructure:"password"` // e.g., "email_password", "app_password"
 	From     string `mapstructure:"from"`     // e.g., "noreply@myapp.com", "support@example.com"
+
+	// Encryption selects how connections are secured: "none", "starttls" or
+	// "tls". Empty infers it from the port: implicit TLS on 465, otherwise
+	// STARTTLS when the server offers it.
+	Encryption string `mapstructure:"encryption"` // e.g., "starttls", "tls"
+
+	// AllowedFromDomains restricts the domain of From when non-empty
+	AllowedFromDomains []string `mapstructure:"allowed_from_domains"` // e.g., ["myapp.com", "mail.myapp.com"]
 }
 
 // AppConfig holds application-specific configuration
 type AppConfig struct {
-	Name        string `mapstructure:"name"`        // e.g., "My Application", "API Gateway", "User Service"
-	Environment string `mapstructure:"environment"` // e.g., "development", "staging", "production", "test"
-	Version     string `mapstructure:"version"`     // e.g., "1.0.0", "v2.1.3", "dev"
-	Debug       bool   `mapstructure:"debug"`       // e.g., true, false
+	Name        string `mapstructure:"name"`                        // e.g., "My Application", "API Gateway", "User Service"
+	Environment string `mapstructure:"environment"`                 // e.g., "development", "staging", "production", "test"
+	Version     string `mapstructure:"version" validate:"required"` // e.g., "1.0.0", "v2.1.3", "dev"
+	Debug       bool   `mapstructure:"debug"`                       // e.g., true, false
+
+	// MaintenanceMode signals handlers to reject traffic (e.g. with 503) while
+	// maintenance is under way. Toggle it with a reload and watch "app.maintenance_mode".
+	MaintenanceMode bool `mapstructure:"maintenance_mode"` // e.g., true, false
+}
+
+// RuntimeConfig holds Go runtime tuning, applied with Manager.ApplyRuntimeConfig
+type RuntimeConfig struct {
+	MaxProcs    int    `mapstructure:"max_procs" validate:"min=0"` // e.g., 4, 8; 0 leaves GOMAXPROCS unchanged
+	MemoryLimit string `mapstructure:"memory_limit"`               // e.g., "512MiB", "2GB"; empty leaves the soft memory limit unchanged
 }
@@ -0,0 +1,19 @@
+package config
+
+import "context"
+
+// contextKey is the unexported key type for values stored in a context
+type contextKey struct{}
+
+// WithContext returns a copy of ctx carrying the current configuration snapshot.
+// The snapshot is unaffected by later reloads, so a request sees one consistent
+// configuration for its whole lifetime.
+func (m *Manager) WithContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, m.GetConfig())
+}
+
+// FromContext returns the configuration snapshot stored by Manager.WithContext
+func FromContext(ctx context.Context) (*Config, bool) {
+	config, ok := ctx.Value(contextKey{}).(*Config)
+	return config, ok && config != nil
+}
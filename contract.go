@@ -0,0 +1,113 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// EnvVarSpec describes one environment variable read by the loader
+type EnvVarSpec struct {
+	Name        string `json:"name"`        // e.g., "JWT_SECRET"
+	Type        string `json:"type"`        // string, integer, boolean, duration or list
+	Default     string `json:"default"`     // e.g., "24h"; empty if none
+	Required    bool   `json:"required"`    // the validator requires a field and no default fills it
+	Description string `json:"description"` // e.g., "Token issuer"
+
+	// Fields are the dotted config paths set from the variable, usually one
+	Fields []string `json:"fields"` // e.g., ["jwt.secret"]
+}
+
+// ExportEnvContract returns a JSON array describing every environment
+// variable the loader reads, in the order of EnvVars, for generating deployment
+// manifests such as Terraform variables or Helm values. List variables take
+// comma-separated values.
+func ExportEnvContract() ([]byte, error) {
+	specs := make([]EnvVarSpec, 0, len(envBindings))
+	index := make(map[string]int, len(envBindings))
+	for _, binding := range envBindings {
+		field, ok := lookupStructField(binding.Field)
+		if !ok {
+			return nil, fmt.Errorf("environment variable %s maps to unknown field %s", binding.Name, binding.Field)
+		}
+
+		if i, seen := index[binding.Name]; seen {
+			specs[i].Fields = append(specs[i].Fields, binding.Field)
+			specs[i].Required = specs[i].Required || (isRequiredField(binding.Field, field) && binding.Default == "")
+			continue
+		}
+		index[binding.Name] = len(specs)
+		specs = append(specs, EnvVarSpec{
+			Name:        binding.Name,
+			Type:        envVarType(field.Type),
+			Default:     binding.Default,
+			Required:    isRequiredField(binding.Field, field) && binding.Default == "",
+			Description: binding.Description,
+			Fields:      []string{binding.Field},
+		})
+	}
+
+	data, err := json.MarshalIndent(specs, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode environment contract: %w", err)
+	}
+	return data, nil
+}
+
+// isRequiredField reports whether the validator requires the field at path,
+// through a built-in rule or a `validate:"required"` tag
+func isRequiredField(path string, field reflect.StructField) bool {
+	return oneOf(path, builtinRequiredFields) || hasTagRule(field, "required")
+}
+
+// envVarType names the kind of value accepted for a field of type t
+func envVarType(t reflect.Type) string {
+	switch {
+	case t == reflect.TypeOf(time.Duration(0)):
+		return "duration"
+	case t.Kind() == reflect.Bool:
+		return "boolean"
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return "integer"
+	case t.Kind() == reflect.Slice:
+		return "list"
+	default:
+		return "string"
+	}
+}
+
+// lookupStructField returns the Config struct field addressed by a dotted path
+func lookupStructField(path string) (reflect.StructField, bool) {
+	t := reflect.TypeOf(Config{})
+	var found reflect.StructField
+	for _, part := range strings.Split(path, ".") {
+		if t.Kind() != reflect.Struct {
+			return reflect.StructField{}, false
+		}
+
+		ok := false
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() && fieldPath("", t.Field(i)) == part {
+				found, ok = t.Field(i), true
+				break
+			}
+		}
+		if !ok {
+			return reflect.StructField{}, false
+		}
+		t = found.Type
+	}
+	return found, true
+}
+
+// hasTagRule reports whether field declares the named rule in its `validate` tag
+func hasTagRule(field reflect.StructField, name string) bool {
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		if ruleName, _, _ := strings.Cut(strings.TrimSpace(rule), "="); ruleName == name {
+			return true
+		}
+	}
+	return false
+}
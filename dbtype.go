@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// DatabaseTypeSpec describes a database type registered with
+// RegisterDatabaseType, such as CockroachDB or Spanner
+type DatabaseTypeSpec struct {
+	// DSNTemplate is a text/template rendered with DSNParams to build
+	// connection strings, e.g.
+	// "postgresql://{{.User}}:{{.Password}}@{{.Host}}:{{.Port}}/{{.DBName}}?sslmode={{.SSLMode}}"
+	DSNTemplate string
+
+	// SSLModes lists the SSL modes accepted for this type. If empty, the
+	// built-in modes (disable, require, verify-ca, verify-full) are accepted.
+	SSLModes []string
+}
+
+// DSNParams holds the connection settings a DSNTemplate is rendered with
+type DSNParams struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	DBName   string
+	SSLMode  string
+}
+
+// databaseType is a registered DatabaseTypeSpec with its parsed template
+type databaseType struct {
+	spec     DatabaseTypeSpec
+	template *template.Template
+}
+
+var (
+	databaseTypesMutex sync.RWMutex
+	databaseTypes      = make(map[string]databaseType)
+)
+
+// RegisterDatabaseType registers a custom database type so that configs with
+// a matching database type (compared case-insensitively) build their DSNs
+// from spec.DSNTemplate and validate their SSL mode against spec.SSLModes.
+// Registering an existing name replaces it.
+func RegisterDatabaseType(name string, spec DatabaseTypeSpec) error {
+	if name == "" {
+		return fmt.Errorf("database type name must not be empty")
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(spec.DSNTemplate)
+	if err != nil {
+		return fmt.Errorf("invalid DSN template for database type %s: %w", name, err)
+	}
+	spec.SSLModes = append([]string(nil), spec.SSLModes...)
+
+	databaseTypesMutex.Lock()
+	defer databaseTypesMutex.Unlock()
+	databaseTypes[strings.ToLower(name)] = databaseType{spec: spec, template: tmpl}
+	return nil
+}
+
+// lookupDatabaseType returns the registered type named dbType, if any
+func lookupDatabaseType(dbType string) (databaseType, bool) {
+	databaseTypesMutex.RLock()
+	defer databaseTypesMutex.RUnlock()
+	t, ok := databaseTypes[strings.ToLower(dbType)]
+	return t, ok
+}
+
+// sslModesFor returns the SSL modes accepted for dbType
+func sslModesFor(dbType string) []string {
+	if t, ok := lookupDatabaseType(dbType); ok && len(t.spec.SSLModes) > 0 {
+		return t.spec.SSLModes
+	}
+	return validSSLModes
+}
+
+// buildDSN renders the connection string for dbType. Registered types use
+// their template; all others use the key=value format. A template that fails
+// to render yields an empty string.
+func buildDSN(dbType string, params DSNParams) string {
+	t, ok := lookupDatabaseType(dbType)
+	if !ok {
+		return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+			params.Host, params.Port, params.User, params.Password, params.DBName, params.SSLMode)
+	}
+
+	var b strings.Builder
+	if err := t.template.Execute(&b, params); err != nil {
+		return ""
+	}
+	return b.String()
+}
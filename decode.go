@@ -0,0 +1,193 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+// decodeHooks returns the decoder option used when unmarshaling file-based
+// configuration, so file values are parsed consistently with environment values
+func (l *Loader) decodeHooks() viper.DecoderConfigOption {
+	hooks := make([]mapstructure.DecodeHookFunc, 0)
+	if l.expandEnv {
+		hooks = append(hooks, l.expandEnvHook)
+	}
+	hooks = append(hooks,
+		rawJSONHook,
+		durationHook,
+		boolHook,
+		mapstructure.StringToSliceHookFunc(","),
+	)
+	return viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(hooks...))
+}
+
+// expandEnvHook expands ${VAR} references in string values from the
+// environment. Unset variables expand to an empty string and produce a
+// warning. Only the braced form is expanded, so other dollar signs, such as
+// in passwords, are kept; "$$" is an escaped "$".
+func (l *Loader) expandEnvHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if from.Kind() != reflect.String {
+		return data, nil
+	}
+	return l.expandEnvRefs(data.(string)), nil
+}
+
+// expandEnvRefs replaces each ${VAR} in s with the value of VAR and each "$$"
+// with "$", leaving any other text unchanged
+func (l *Loader) expandEnvRefs(s string) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		if s[i+1] == '$' {
+			b.WriteByte('$')
+			i++
+			continue
+		}
+		end := strings.IndexByte(s[i+1:], '}')
+		if s[i+1] != '{' || end < 0 || !validEnvName(s[i+2:i+1+end]) {
+			b.WriteByte('$')
+			continue
+		}
+
+		name := s[i+2 : i+1+end]
+		value, ok := l.lookupEnv(name)
+		if !ok {
+			l.warnings = append(l.warnings, fmt.Sprintf("config references unset environment variable %s", name))
+		}
+		b.WriteString(value)
+		i += 1 + end
+	}
+	return b.String()
+}
+
+// validEnvName reports whether name is a shell-style variable name, e.g. DB_PASSWORD
+func validEnvName(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for _, r := range name {
+		if r != '_' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// durationHook decodes duration strings using the extended duration parser,
+// which accepts day units such as "7d". Numbers, as written by TOML and JSON
+// files that lack a duration type, are taken as nanoseconds and must be whole.
+func durationHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if to != reflect.TypeOf(time.Duration(0)) {
+		return data, nil
+	}
+
+	switch from.Kind() {
+	case reflect.String:
+		return parseDuration(data.(string))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return time.Duration(reflect.ValueOf(data).Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return time.Duration(reflect.ValueOf(data).Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		f := reflect.ValueOf(data).Float()
+		if f != math.Trunc(f) {
+			return nil, fmt.Errorf("invalid duration %v: numeric durations are whole nanoseconds; use a string such as \"30s\"", f)
+		}
+		return time.Duration(f), nil
+	default:
+		return data, nil
+	}
+}
+
+// boolHook decodes string and numeric values into booleans using the same
+// tokens as environment variables, e.g. "yes", "on" or 1
+func boolHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if to.Kind() != reflect.Bool {
+		return data, nil
+	}
+
+	switch from.Kind() {
+	case reflect.String:
+		return parseBool(strings.TrimSpace(data.(string)))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return parseBool(strconv.FormatInt(reflect.ValueOf(data).Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return parseBool(strconv.FormatUint(reflect.ValueOf(data).Uint(), 10))
+	default:
+		return data, nil
+	}
+}
+
+// rawJSONHook re-encodes arbitrary values decoded into a json.RawMessage, so
+// extension sections can be decoded later into caller-defined types
+func rawJSONHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if to != reflect.TypeOf(json.RawMessage{}) {
+		return data, nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode extension: %w", err)
+	}
+	return json.RawMessage(raw), nil
+}
+
+// decodeInto decodes a resolved configuration into out, an arbitrary struct
+// pointer, matching fields by mapstructure tag. Weak typing lets, e.g., the
+// string port "8080" decode into an int field.
+func decodeInto(config *Config, out interface{}) error {
+	return decodeValue(configMap(reflect.ValueOf(*config)), out)
+}
+
+// decodeValue decodes input, as produced by configMap, into out
+func decodeValue(input, out interface{}) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           out,
+		TagName:          "mapstructure",
+		WeaklyTypedInput: true,
+		DecodeHook:       mapstructure.ComposeDecodeHookFunc(durationHook, rawJSONHook),
+	})
+	if err != nil {
+		return fmt.Errorf("invalid decode target: %w", err)
+	}
+
+	if err := decoder.Decode(input); err != nil {
+		return fmt.Errorf("failed to decode config: %w", err)
+	}
+	return nil
+}
+
+// configMap converts a struct value into nested maps keyed by the dotted path
+// segments used throughout the package
+func configMap(v reflect.Value) map[string]interface{} {
+	t := v.Type()
+	m := make(map[string]interface{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := fieldPath("", field)
+		if field.Type.Kind() == reflect.Struct {
+			m[name] = configMap(v.Field(i))
+			continue
+		}
+		m[name] = v.Field(i).Interface()
+	}
+	return m
+}
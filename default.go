@@ -0,0 +1,81 @@
+package config
+
+import "sync"
+
+var (
+	defaultManagerMutex sync.RWMutex
+	defaultManager      *Manager
+)
+
+// Default returns the package-wide manager, creating it on first use. The
+// same manager is returned until SetDefault replaces it, so watchers and
+// reloads registered through it apply to every caller of the package-level
+// accessors such as GetServerConfig.
+func Default() *Manager {
+	defaultManagerMutex.RLock()
+	m := defaultManager
+	defaultManagerMutex.RUnlock()
+	if m != nil {
+		return m
+	}
+
+	defaultManagerMutex.Lock()
+	defer defaultManagerMutex.Unlock()
+	if defaultManager == nil {
+		defaultManager = NewManager()
+	}
+	return defaultManager
+}
+
+// SetDefault replaces the package-wide manager returned by Default. Passing
+// nil discards it, so the next call to Default creates a new one.
+func SetDefault(m *Manager) {
+	defaultManagerMutex.Lock()
+	defer defaultManagerMutex.Unlock()
+	defaultManager = m
+}
+
+// GetConfig returns the current configuration of the default manager
+func GetConfig() *Config {
+	return Default().GetConfig()
+}
+
+// GetServerConfig returns the server configuration of the default manager
+func GetServerConfig() ServerConfig {
+	return Default().GetServerConfig()
+}
+
+// GetDatabaseConfig returns the database configuration of the default manager
+func GetDatabaseConfig() DatabaseConfig {
+	return Default().GetDatabaseConfig()
+}
+
+// GetRedisConfig returns the Redis configuration of the default manager
+func GetRedisConfig() RedisConfig {
+	return Default().GetRedisConfig()
+}
+
+// GetLogConfig returns the logging configuration of the default manager
+func GetLogConfig() LogConfig {
+	return Default().GetLogConfig()
+}
+
+// GetJWTConfig returns the JWT configuration of the default manager
+func GetJWTConfig() JWTConfig {
+	return Default().GetJWTConfig()
+}
+
+// GetEmailConfig returns the email configuration of the default manager
+func GetEmailConfig() EmailConfig {
+	return Default().GetEmailConfig()
+}
+
+// GetAppConfig returns the application configuration of the default manager
+func GetAppConfig() AppConfig {
+	return Default().GetAppConfig()
+}
+
+// GetRuntimeConfig returns the Go runtime configuration of the default manager
+func GetRuntimeConfig() RuntimeConfig {
+	return Default().GetRuntimeConfig()
+}
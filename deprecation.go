@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// deprecatedField describes a field registered with RegisterDeprecatedField
+type deprecatedField struct {
+	replacement  string
+	sinceVersion string
+}
+
+var (
+	deprecatedFieldsMutex sync.RWMutex
+	deprecatedFields      = make(map[string]deprecatedField)
+)
+
+// RegisterDeprecatedField marks the field at path (e.g. "database.host") as
+// deprecated since sinceVersion. Validation then warns whenever the field is
+// set, pointing to replacement if it is not empty.
+func RegisterDeprecatedField(path, replacement, sinceVersion string) {
+	deprecatedFieldsMutex.Lock()
+	defer deprecatedFieldsMutex.Unlock()
+	deprecatedFields[path] = deprecatedField{replacement: replacement, sinceVersion: sinceVersion}
+}
+
+// validateDeprecations warns about every deprecated field that is set
+func (v *Validator) validateDeprecations(config *Config) {
+	deprecatedFieldsMutex.RLock()
+	paths := make([]string, 0, len(deprecatedFields))
+	for path := range deprecatedFields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	fields := make([]deprecatedField, len(paths))
+	for i, path := range paths {
+		fields[i] = deprecatedFields[path]
+	}
+	deprecatedFieldsMutex.RUnlock()
+
+	for i, path := range paths {
+		value, ok := lookupField(config, path)
+		if !ok || value.IsZero() {
+			continue
+		}
+
+		msg := fmt.Sprintf("%s is deprecated", path)
+		if fields[i].sinceVersion != "" {
+			msg += fmt.Sprintf(" since %s", fields[i].sinceVersion)
+		}
+		if fields[i].replacement != "" {
+			msg += fmt.Sprintf("; use %s instead", fields[i].replacement)
+		}
+		v.addWarning(path, msg)
+	}
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// redactedValue replaces secret values in diffs and exports
+const redactedValue = "[REDACTED]"
+
+// FieldChange describes a single configuration field that differs between two configurations
+type FieldChange struct {
+	Field    string      `json:"field"`     // e.g., "server.port", "database.host"
+	OldValue interface{} `json:"old_value"` // e.g., "8080"
+	NewValue interface{} `json:"new_value"` // e.g., "9090"
+}
+
+// Diff returns the field-level differences between two configurations.
+// Fields are identified by their dotted mapstructure path. A nil configuration
+// is treated as an empty one.
+func Diff(oldConfig, newConfig *Config) []FieldChange {
+	if oldConfig == nil {
+		oldConfig = &Config{}
+	}
+	if newConfig == nil {
+		newConfig = &Config{}
+	}
+
+	changes := make([]FieldChange, 0)
+	diffStruct("", reflect.ValueOf(*oldConfig), reflect.ValueOf(*newConfig), &changes)
+	return changes
+}
+
+// Equal reports whether c and other hold identical values. Two nil
+// configurations are equal; a nil and a non-nil configuration are not.
+func (c *Config) Equal(other *Config) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	return reflect.DeepEqual(*c, *other)
+}
+
+// diffStruct walks two struct values of the same type and records differing leaf fields
+func diffStruct(prefix string, oldValue, newValue reflect.Value, changes *[]FieldChange) {
+	t := oldValue.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		path := fieldPath(prefix, field)
+		oldField := oldValue.Field(i)
+		newField := newValue.Field(i)
+
+		if field.Type.Kind() == reflect.Struct {
+			diffStruct(path, oldField, newField, changes)
+			continue
+		}
+
+		if !reflect.DeepEqual(oldField.Interface(), newField.Interface()) {
+			*changes = append(*changes, FieldChange{
+				Field:    path,
+				OldValue: oldField.Interface(),
+				NewValue: newField.Interface(),
+			})
+		}
+	}
+}
+
+// fieldPath returns the dotted path of a struct field using its mapstructure tag
+func fieldPath(prefix string, field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// matchesField reports whether a changed field is covered by the given field or section path
+func matchesField(changed, field string) bool {
+	return changed == field || strings.HasPrefix(changed, field+".")
+}
+
+// CompareFiles loads two configuration files and returns their field-level
+// differences, with secret values redacted. It is intended for detecting
+// configuration drift between environments, e.g. in CI.
+func CompareFiles(pathA, pathB string) ([]FieldChange, error) {
+	configA, err := NewLoader().LoadFromFile(pathA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", pathA, err)
+	}
+
+	configB, err := NewLoader().LoadFromFile(pathB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", pathB, err)
+	}
+
+	return RedactChanges(Diff(configA, configB)), nil
+}
+
+// DiffAgainstFile loads the configuration file at path and returns how it
+// differs from the current configuration, with secret values redacted. Old
+// values come from the running configuration and new values from the file,
+// so an empty result means the running configuration has not drifted.
+func (m *Manager) DiffAgainstFile(path string) ([]FieldChange, error) {
+	current := m.GetConfig()
+	if current == nil {
+		return nil, fmt.Errorf("no configuration loaded")
+	}
+
+	fileConfig, err := NewLoader().LoadFromFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", path, err)
+	}
+
+	return RedactChanges(Diff(current, fileConfig)), nil
+}
+
+// RedactChanges returns a copy of changes with the values of secret fields,
+// and of secret-looking keys within extensions, redacted
+func RedactChanges(changes []FieldChange) []FieldChange {
+	redacted := make([]FieldChange, len(changes))
+	for i, change := range changes {
+		if isSecretField(change.Field) {
+			change.OldValue = redactedValue
+			change.NewValue = redactedValue
+		}
+		if oldExtensions, ok := change.OldValue.(map[string]json.RawMessage); ok {
+			change.OldValue = redactExtensions(oldExtensions)
+		}
+		if newExtensions, ok := change.NewValue.(map[string]json.RawMessage); ok {
+			change.NewValue = redactExtensions(newExtensions)
+		}
+		redacted[i] = change
+	}
+	return redacted
+}
+
+// secretURLFields lists URL fields that may embed a password in their userinfo
+var secretURLFields = []string{"redis.url"}
+
+// isSecretField reports whether the dotted field path holds a secret value
+func isSecretField(path string) bool {
+	name := path[strings.LastIndex(path, ".")+1:]
+	return strings.Contains(name, "password") || strings.Contains(name, "secret") || oneOf(path, secretURLFields)
+}
@@ -0,0 +1,253 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ExportDotEnv renders the current configuration as a .env file, one
+// KEY=value line per environment variable read by the loader. Empty fields
+// with a non-empty default are written as KEY="", which LoadDotEnv reads back
+// as empty rather than as the default. Secrets are included in clear text; use
+// ExportDotEnvRedacted for output that may be shared.
+func (m *Manager) ExportDotEnv() string {
+	return dotEnv(m.GetConfig(), false)
+}
+
+// ExportDotEnvRedacted renders the current configuration as a .env file with
+// secret values replaced by "[REDACTED]"
+func (m *Manager) ExportDotEnvRedacted() string {
+	return dotEnv(m.GetConfig(), true)
+}
+
+// EnvironSlice returns the current configuration as KEY=value entries using
+// the variable names read by LoadFromEnvironment, suitable for exec.Cmd.Env,
+// so that a subprocess using this package loads the same configuration.
+// Secrets are included in clear text. It returns nil if no configuration is
+// loaded. Append the result to os.Environ() to keep the rest of the environment.
+func (m *Manager) EnvironSlice() []string {
+	config := m.GetConfig()
+	if config == nil {
+		return nil
+	}
+
+	pairs := envPairs(config, false)
+	environ := make([]string, len(pairs))
+	for i, pair := range pairs {
+		environ[i] = pair.name + "=" + pair.value
+	}
+	return environ
+}
+
+// envPair is a single environment variable and its value. emptyOverride
+// marks empty values whose binding defaults to something else.
+type envPair struct {
+	name, value   string
+	emptyOverride bool
+}
+
+// dotEnv renders config as KEY=value lines
+func dotEnv(config *Config, redact bool) string {
+	if config == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, pair := range envPairs(config, redact) {
+		if pair.emptyOverride {
+			fmt.Fprintf(&b, "%s=\"\"\n", pair.name)
+			continue
+		}
+		writeDotEnvLine(&b, pair.name, pair.value)
+	}
+	return b.String()
+}
+
+// envPairs returns the environment variables representing config in binding
+// declaration order, followed by any per-operation timeouts. Variables bound
+// to several fields are returned once, taking the value of their app.*
+// binding if they have one and of their first binding otherwise.
+func envPairs(config *Config, redact bool) []envPair {
+	exported := make(map[string]envBinding, len(envBindings))
+	for _, binding := range envBindings {
+		if current, ok := exported[binding.Name]; !ok || (!strings.HasPrefix(current.Field, "app.") && strings.HasPrefix(binding.Field, "app.")) {
+			exported[binding.Name] = binding
+		}
+	}
+
+	pairs := make([]envPair, 0, len(envBindings)+len(config.Server.Timeouts))
+	seen := make(map[string]bool, len(envBindings))
+	for _, binding := range envBindings {
+		if seen[binding.Name] {
+			continue
+		}
+		seen[binding.Name] = true
+		binding = exported[binding.Name]
+
+		field, ok := lookupField(config, binding.Field)
+		if !ok {
+			continue
+		}
+		value := fmt.Sprint(field.Interface())
+		if list, ok := field.Interface().([]string); ok {
+			value = strings.Join(list, ",")
+		}
+		if redact && isSecretField(binding.Field) && value != "" {
+			value = redactedValue
+		}
+		pairs = append(pairs, envPair{binding.Name, value, value == "" && binding.Default != ""})
+	}
+
+	names := make([]string, 0, len(config.Server.Timeouts))
+	for name := range config.Server.Timeouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		pairs = append(pairs, envPair{name: operationTimeoutPrefix + strings.ToUpper(name), value: config.Server.Timeouts[name].String()})
+	}
+
+	return pairs
+}
+
+// writeDotEnvLine writes a single KEY=value line, quoting values that would
+// otherwise be misread by .env parsers
+func writeDotEnvLine(b *strings.Builder, name, value string) {
+	if strings.ContainsAny(value, " \t\n\"'#$\\") {
+		value = strconv.Quote(value)
+	}
+	fmt.Fprintf(b, "%s=%s\n", name, value)
+}
+
+// LoadDotEnv reads KEY=value pairs from a .env file into the process
+// environment so that a following environment or hybrid load picks them up.
+// Blank lines, "#" comments and "export " prefixes are ignored; values may be
+// double-quoted (with Go-style escapes), single-quoted (literal) or bare, in
+// which case a trailing " #" comment is stripped. Variables already set to a
+// non-empty value take precedence over the file. A quoted empty value, as in
+// KEY="", empties its field on this loader's environment and hybrid loads
+// instead of leaving it at the default, as written by ExportDotEnv.
+func (l *Loader) LoadDotEnv(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open .env file: %w", err)
+	}
+	defer file.Close()
+
+	values, err := parseDotEnv(file)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	for _, entry := range values {
+		if os.Getenv(entry.key) != "" {
+			continue
+		}
+		if err := os.Setenv(entry.key, entry.value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", entry.key, err)
+		}
+		if entry.value == "" && entry.quoted {
+			if l.emptyEnv == nil {
+				l.emptyEnv = make(map[string]bool)
+			}
+			l.emptyEnv[entry.key] = true
+		} else {
+			delete(l.emptyEnv, entry.key)
+		}
+	}
+	return nil
+}
+
+// LoadDotEnv reads a .env file into the process environment for the
+// manager's loader; see Loader.LoadDotEnv
+func (m *Manager) LoadDotEnv(path string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.loader.LoadDotEnv(path)
+}
+
+// setExplicitlyEmpty empties the field of binding if LoadDotEnv read a quoted
+// empty value for its variable, reporting whether it did. Fields without an
+// empty value, such as numbers, are left unchanged.
+func (l *Loader) setExplicitlyEmpty(config *Config, binding envBinding) bool {
+	if l.envSnapshot != nil || !l.emptyEnv[binding.Name] {
+		return false
+	}
+	return setFieldFromString(config, binding.Field, "") == nil
+}
+
+// dotEnvEntry is a single KEY=value line of a .env file. quoted records
+// whether the value was quoted.
+type dotEnvEntry struct {
+	key, value string
+	quoted     bool
+}
+
+// parseDotEnv parses .env content into entries in file order
+func parseDotEnv(r io.Reader) ([]dotEnvEntry, error) {
+	var values []dotEnvEntry
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		key, raw, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=value", lineNum)
+		}
+
+		raw = strings.TrimSpace(raw)
+		value, err := parseDotEnvValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		quoted := strings.HasPrefix(raw, `"`) || strings.HasPrefix(raw, "'")
+		values = append(values, dotEnvEntry{key, value, quoted})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// parseDotEnvValue decodes the value part of a .env line
+func parseDotEnvValue(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		for i := 1; i < len(raw); i++ {
+			switch raw[i] {
+			case '\\':
+				i++
+			case '"':
+				if rest := strings.TrimSpace(raw[i+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+					return "", fmt.Errorf("unexpected characters after quoted value: %s", rest)
+				}
+				return strconv.Unquote(raw[:i+1])
+			}
+		}
+		return "", fmt.Errorf("unterminated quoted value")
+	case strings.HasPrefix(raw, "'"):
+		end := strings.Index(raw[1:], "'")
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		if rest := strings.TrimSpace(raw[end+2:]); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected characters after quoted value: %s", rest)
+		}
+		return raw[1 : end+1], nil
+	default:
+		if i := strings.Index(raw, " #"); i >= 0 {
+			raw = raw[:i]
+		}
+		return strings.TrimSpace(raw), nil
+	}
+}
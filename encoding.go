@@ -0,0 +1,85 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// utf8BOM is the byte order mark some Windows editors prepend to UTF-8 files
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// configText strips a leading UTF-8 byte order mark from data and rejects
+// content that is not UTF-8, which the parsers would otherwise report with
+// confusing syntax errors
+func configText(data []byte) ([]byte, error) {
+	if bytes.HasPrefix(data, []byte{0xFF, 0xFE}) || bytes.HasPrefix(data, []byte{0xFE, 0xFF}) {
+		return nil, errors.New("config is UTF-16 encoded; save it as UTF-8")
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+	if !utf8.Valid(data) {
+		return nil, errors.New("config is not valid UTF-8; save it as UTF-8")
+	}
+	return data, nil
+}
+
+// configFileFormats lists the formats config files may use
+var configFileFormats = []string{"yaml", "yml", "json", "toml"}
+
+// configFormat normalizes an explicitly given format, e.g. "YAML" or ".json",
+// and checks that it is one of configFileFormats
+func configFormat(format string) (string, error) {
+	format = strings.ToLower(strings.TrimPrefix(format, "."))
+	if !containsString(configFileFormats, format) {
+		return "", fmt.Errorf("unsupported config format %q: use one of %s", format, strings.Join(configFileFormats, ", "))
+	}
+	return format, nil
+}
+
+// configFileFormat returns the format of the config file at path: format if
+// set, otherwise the one named by the file extension. Either must be one of
+// configFileFormats.
+func configFileFormat(path, format string) (string, error) {
+	if format != "" {
+		return configFormat(format)
+	}
+
+	ext := filepath.Ext(path)
+	if ext == "" {
+		return "", fmt.Errorf("cannot infer config format of %s: missing file extension (use LoadFromFileWithFormat)", path)
+	}
+	format = strings.ToLower(strings.TrimPrefix(ext, "."))
+	if !containsString(configFileFormats, format) {
+		return "", fmt.Errorf("unsupported config format %q: use a .yaml, .yml, .json or .toml file", ext)
+	}
+	return format, nil
+}
+
+// readConfigFile reads the file at path into viper in the given format, or
+// the one named by its extension if format is empty. With merge set, its
+// settings are merged into those already read.
+func (l *Loader) readConfigFile(path, format string, merge bool) error {
+	format, err := configFileFormat(path, format)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if data, err = configText(data); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	l.viper.SetConfigFile(path)
+	l.viper.SetConfigType(format)
+	if merge {
+		return l.viper.MergeConfig(bytes.NewReader(data))
+	}
+	return l.viper.ReadConfig(bytes.NewReader(data))
+}
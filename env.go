@@ -0,0 +1,256 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// envBinding maps an environment variable to a configuration field
+type envBinding struct {
+	Name        string // e.g., "SERVER_PORT"
+	Field       string // dotted config path, e.g., "server.port"
+	Default     string // e.g., "8080"
+	Description string // e.g., "Port the HTTP server listens on"
+}
+
+// envBindings is the authoritative list of environment variables read by the
+// loader. LoadFromEnvironment and EnvVars are both derived from it.
+var envBindings = []envBinding{
+	// Server
+	{"SERVER_PORT", "server.port", "8080", "Port the HTTP server listens on"},
+	{"SERVER_HOST", "server.host", "0.0.0.0", "Interface the HTTP server binds to"},
+	{"SERVER_READ_TIMEOUT", "server.read_timeout", "30s", "Maximum duration for reading a request"},
+	{"SERVER_WRITE_TIMEOUT", "server.write_timeout", "30s", "Maximum duration before timing out writes of a response"},
+	{"SERVER_IDLE_TIMEOUT", "server.idle_timeout", "60s", "Maximum time to wait for the next request on keep-alive connections"},
+	{"SERVER_MAX_HEADER_BYTES", "server.max_header_bytes", "1048576", "Maximum size of request headers in bytes"},
+	{"SERVER_TLS_CERT_FILE", "server.tls_cert_file", "", "PEM certificate file; TLS is enabled when set together with the key file"},
+	{"SERVER_TLS_KEY_FILE", "server.tls_key_file", "", "PEM private key file for the TLS certificate"},
+	{"SERVER_MIN_TLS_VERSION", "server.min_tls_version", "", "Minimum TLS version: 1.0, 1.1, 1.2 or 1.3; empty uses crypto/tls's default"},
+	{"SERVER_BIND_ALL", "server.bind_all", "false", "Acknowledge binding to all interfaces (0.0.0.0) in production"},
+
+	// Read/Write Database Configuration
+	{"DB_WRITE_HOST", "database.write_host", "", "Write database host (INSERT/UPDATE/DELETE)"},
+	{"DB_WRITE_PORT", "database.write_port", "5432", "Write database port"},
+	{"DB_WRITE_USER", "database.write_user", "", "Write database user"},
+	{"DB_WRITE_PASSWORD", "database.write_password", "", "Write database password"},
+	{"DB_WRITE_NAME", "database.write_dbname", "", "Write database name"},
+
+	{"DB_READ_HOST", "database.read_host", "", "Read database host (SELECT)"},
+	{"DB_READ_PORT", "database.read_port", "5432", "Read database port"},
+	{"DB_READ_USER", "database.read_user", "", "Read database user"},
+	{"DB_READ_PASSWORD", "database.read_password", "", "Read database password"},
+	{"DB_READ_NAME", "database.read_dbname", "", "Read database name"},
+	{"DB_READ_REPLICAS", "database.read_replicas", "", "Comma-separated read replica host:port list"},
+
+	// Legacy Database Configuration (Backward Compatibility)
+	{"DB_HOST", "database.host", "localhost", "Database host (legacy single-database configuration)"},
+	{"DB_PORT", "database.port", "5432", "Database port"},
+	{"DB_USER", "database.user", "postgres", "Database user"},
+	{"DB_PASSWORD", "database.password", "", "Database password"},
+	{"DB_NAME", "database.dbname", "app", "Database name"},
+
+	// Database Type and Environment
+	{"DB_SSL_MODE", "database.sslmode", "disable", "SSL mode: disable, require, verify-ca or verify-full"},
+	{"DB_MAX_CONNS", "database.max_conns", "10", "Maximum number of open database connections"},
+	{"DB_TYPE", "database.type", "postgresql", "Database type, e.g. postgresql, mysql, sqlserver or sqlite"},
+	{"APP_ENVIRONMENT", "database.environment", "development", "Deployment environment: development, staging, production or test"},
+	{"DATABASE_CONFIG_TYPE", "database.config_type", "auto_detect", "Database configuration type: read_write, legacy or auto_detect"},
+
+	// Database Connection Pool
+	{"DB_MAX_IDLE_CONNS", "database.max_idle_conns", "2", "Maximum number of idle database connections; negative keeps none"},
+	{"DB_CONN_MAX_LIFETIME", "database.conn_max_lifetime", "30m", "Maximum time a database connection may be reused"},
+	{"DB_CONN_MAX_IDLE_TIME", "database.conn_max_idle_time", "5m", "Maximum time a database connection may sit idle"},
+
+	// Redis
+	{"REDIS_HOST", "redis.host", "localhost", "Redis host"},
+	{"REDIS_PORT", "redis.port", "6379", "Redis port"},
+	{"REDIS_PASSWORD", "redis.password", "", "Redis password"},
+	{"REDIS_DB", "redis.db", "0", "Redis database number (0-15)"},
+	{"REDIS_URL", "redis.url", "", "Redis URL; its path selects the database, e.g. redis://host:6379/2"},
+	{"REDIS_POOL_SIZE", "redis.pool_size", "10", "Maximum number of Redis connections; 0 uses the client's default"},
+	{"REDIS_MIN_IDLE_CONNS", "redis.min_idle_conns", "0", "Minimum number of idle Redis connections"},
+
+	// Log
+	{"LOG_LEVEL", "log.level", "info", "Log level: debug, info, warn, error, fatal or panic"},
+	{"LOG_FORMAT", "log.format", "json", "Log format: json, text or console"},
+	{"LOG_OUTPUT_PATH", "log.output_path", "", "Log file path; empty logs to stdout"},
+
+	// JWT
+	{"JWT_SECRET", "jwt.secret", "your-secret-key", "Secret used to sign JWTs (at least 32 characters)"},
+	{"JWT_EXPIRATION", "jwt.expiration", "24h", "Token lifetime, e.g. 24h or 7d"},
+	{"JWT_ISSUER", "jwt.issuer", "app", "Token issuer"},
+	{"JWT_ALGORITHM", "jwt.algorithm", "HS256", "Signing algorithm: HS256, HS384, HS512, RS256, RS384, RS512, ES256, ES384 or ES512"},
+	{"JWT_PRIVATE_KEY_PATH", "jwt.private_key_path", "", "PEM private key file for RS* and ES* algorithms"},
+	{"JWT_PUBLIC_KEY_PATH", "jwt.public_key_path", "", "PEM public key file for RS* and ES* algorithms; derived from the private key if empty"},
+
+	// Email
+	{"EMAIL_HOST", "email.host", "", "SMTP host; leave empty to disable email (required in production by default)"},
+	{"EMAIL_PORT", "email.port", "587", "SMTP port"},
+	{"SMTP_ENCRYPTION", "email.encryption", "", "SMTP encryption: none, starttls or tls; empty uses implicit TLS on port 465 and STARTTLS when offered otherwise"},
+	{"EMAIL_USERNAME", "email.username", "", "SMTP username"},
+	{"EMAIL_PASSWORD", "email.password", "", "SMTP password"},
+	{"EMAIL_FROM", "email.from", "", "Sender address for outgoing email"},
+	{"EMAIL_ALLOWED_FROM_DOMAINS", "email.allowed_from_domains", "", "Comma-separated domains the sender address must belong to; empty allows any"},
+
+	// App
+	{"APP_NAME", "app.name", "app", "Application name"},
+	{"APP_ENVIRONMENT", "app.environment", "development", "Deployment environment: development, staging, production or test"},
+	{"APP_VERSION", "app.version", "1.0.0", "Application version"},
+	{"APP_DEBUG", "app.debug", "false", "Enable debug mode"},
+	{"APP_MAINTENANCE_MODE", "app.maintenance_mode", "false", "Enable maintenance mode; handlers should answer 503"},
+
+	// Runtime
+	{"RUNTIME_MAX_PROCS", "runtime.max_procs", "0", "GOMAXPROCS to apply; 0 leaves it unchanged"},
+	{"RUNTIME_MEMORY_LIMIT", "runtime.memory_limit", "", "Soft memory limit to apply, e.g. 512MiB or 2GB; empty leaves it unchanged"},
+}
+
+// operationTimeoutPrefix is the environment variable prefix for named per-operation timeouts
+const operationTimeoutPrefix = "SERVER_TIMEOUT_"
+
+// EnvVars returns the name of every environment variable the loader reads,
+// in declaration order and without duplicates
+func EnvVars() []string {
+	seen := make(map[string]bool, len(envBindings))
+	names := make([]string, 0, len(envBindings))
+	for _, binding := range envBindings {
+		if seen[binding.Name] {
+			continue
+		}
+		seen[binding.Name] = true
+		names = append(names, binding.Name)
+	}
+	return names
+}
+
+// bindingDefault returns the default value bound to a dotted field path
+func bindingDefault(field string) string {
+	for _, binding := range envBindings {
+		if binding.Field == field {
+			return binding.Default
+		}
+	}
+	return ""
+}
+
+// loadOperationTimeouts merges SERVER_TIMEOUT_<NAME> variables into
+// config.Server.Timeouts and reports whether any were found
+func (l *Loader) loadOperationTimeouts(config *Config) bool {
+	found := false
+	for _, entry := range l.environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(key, operationTimeoutPrefix) || value == "" {
+			continue
+		}
+		l.recordEnvRead(key, value)
+		name := strings.ToLower(strings.TrimPrefix(key, operationTimeoutPrefix))
+		if name == "" {
+			continue
+		}
+
+		timeout, err := parseDuration(value)
+		if err != nil {
+			l.envErrors = append(l.envErrors, fmt.Errorf("%s: %w", key, err))
+			continue
+		}
+		if config.Server.Timeouts == nil {
+			config.Server.Timeouts = make(map[string]time.Duration)
+		}
+		config.Server.Timeouts[name] = timeout
+		found = true
+	}
+	return found
+}
+
+// lookupField returns the settable struct field addressed by a dotted mapstructure path
+func lookupField(config *Config, path string) (reflect.Value, bool) {
+	v := reflect.ValueOf(config).Elem()
+	for _, part := range strings.Split(path, ".") {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+
+		found := false
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() && fieldPath("", t.Field(i)) == part {
+				v = v.Field(i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return reflect.Value{}, false
+		}
+	}
+	return v, true
+}
+
+// negativeIntFields lists the integer fields whose negative values are
+// meaningful, e.g. database.max_idle_conns, where -1 disables idle connections
+var negativeIntFields = map[string]bool{
+	"database.max_idle_conns": true,
+}
+
+// setFieldFromString parses a raw string value into the field addressed by path
+func setFieldFromString(config *Config, path, value string) error {
+	field, ok := lookupField(config, path)
+	if !ok {
+		return fmt.Errorf("unknown config field: %s", path)
+	}
+
+	switch {
+	case field.Type() == reflect.TypeOf(time.Duration(0)):
+		duration, err := parseDuration(value)
+		if err != nil {
+			return err
+		}
+		if duration < 0 {
+			return fmt.Errorf("duration must not be negative: %s", value)
+		}
+		field.SetInt(int64(duration))
+	case field.Kind() == reflect.String:
+		field.SetString(value)
+	case field.Kind() == reflect.Int:
+		intValue, err := parseInt(value)
+		if err != nil {
+			return err
+		}
+		if intValue < 0 && !negativeIntFields[path] {
+			return fmt.Errorf("value must not be negative: %s", value)
+		}
+		field.SetInt(int64(intValue))
+	case field.Kind() == reflect.Bool:
+		boolValue, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(boolValue)
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		field.Set(reflect.ValueOf(parseList(value)))
+	default:
+		return fmt.Errorf("unsupported field type %s for %s", field.Type(), path)
+	}
+	return nil
+}
+
+// setFieldValue sets the field addressed by path to value. Strings are parsed
+// like environment variables; other values must be assignable to the field.
+func setFieldValue(config *Config, path string, value interface{}) error {
+	if s, ok := value.(string); ok {
+		return setFieldFromString(config, path, s)
+	}
+
+	field, ok := lookupField(config, path)
+	if !ok {
+		return fmt.Errorf("unknown config field: %s", path)
+	}
+
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || !v.Type().AssignableTo(field.Type()) {
+		return fmt.Errorf("cannot use %T as %s for %s", value, field.Type(), path)
+	}
+	field.Set(v)
+	return nil
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exampleSecret is the placeholder written for secret fields in example configs
+const exampleSecret = "change-me"
+
+// exampleJWTSecret is the placeholder written for the JWT secret in example configs
+const exampleJWTSecret = "change-me-to-a-random-secret-of-at-least-32-characters"
+
+// WriteExampleConfig writes a fully-populated starter configuration to path.
+// Supported formats are "yaml" (with a comment describing each field) and "json".
+func WriteExampleConfig(path string, format string) error {
+	var (
+		data []byte
+		err  error
+	)
+
+	switch strings.ToLower(format) {
+	case "yaml", "yml":
+		data, err = exampleYAML()
+	case "json":
+		data, err = exampleJSON()
+	default:
+		return fmt.Errorf("unsupported example config format %q", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write example config: %w", err)
+	}
+	return nil
+}
+
+// exampleValue returns the raw value written for a binding: its default,
+// or a placeholder for secrets
+func exampleValue(binding envBinding) string {
+	switch {
+	case binding.Field == "jwt.secret":
+		return exampleJWTSecret
+	case oneOf(binding.Field, secretURLFields):
+		// a placeholder would not parse as a URL; leave it unset
+		return binding.Default
+	case isSecretField(binding.Field):
+		return exampleSecret
+	default:
+		return binding.Default
+	}
+}
+
+// exampleConfig returns a configuration holding the example values
+func exampleConfig() (*Config, error) {
+	config := &Config{}
+	for _, binding := range envBindings {
+		if err := setFieldFromString(config, binding.Field, exampleValue(binding)); err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+// exampleYAML renders the example configuration as commented YAML
+func exampleYAML() ([]byte, error) {
+	config, err := exampleConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Example configuration. Every field can also be set through the\n")
+	buf.WriteString("# environment variable named in its comment.\n")
+
+	section := ""
+	for _, binding := range envBindings {
+		parts := strings.SplitN(binding.Field, ".", 2)
+		if parts[0] != section {
+			section = parts[0]
+			fmt.Fprintf(&buf, "\n%s:\n", section)
+		}
+
+		field, _ := lookupField(config, binding.Field)
+		value := exampleValue(binding)
+		if field.Kind() == reflect.String || field.Type() == reflect.TypeOf(time.Duration(0)) {
+			value = strconv.Quote(value)
+		}
+		fmt.Fprintf(&buf, "  # %s (env: %s)\n", binding.Description, binding.Name)
+		fmt.Fprintf(&buf, "  %s: %s\n", parts[1], value)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// exampleJSON renders the example configuration as indented JSON
+func exampleJSON() ([]byte, error) {
+	config, err := exampleConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	sections := make(map[string]map[string]interface{})
+	for _, binding := range envBindings {
+		parts := strings.SplitN(binding.Field, ".", 2)
+		if sections[parts[0]] == nil {
+			sections[parts[0]] = make(map[string]interface{})
+		}
+
+		field, _ := lookupField(config, binding.Field)
+		value := field.Interface()
+		if _, ok := value.(time.Duration); ok {
+			value = exampleValue(binding)
+		}
+		sections[parts[0]][parts[1]] = value
+	}
+
+	data, err := json.MarshalIndent(sections, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to render example config: %w", err)
+	}
+	return append(data, '\n'), nil
+}
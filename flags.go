@@ -0,0 +1,40 @@
+package config
+
+import "github.com/spf13/pflag"
+
+// defaultConfigPath is the file read by FileStrategy when neither the
+// --config flag nor CONFIG_PATH names one
+const defaultConfigPath = "config.yaml"
+
+// BindFlags registers a --config flag on fs naming the configuration file.
+// Once fs is parsed, a non-empty --config takes precedence over CONFIG_PATH,
+// which takes precedence over the default "config.yaml".
+func (l *Loader) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&l.configFlag, "config", "", "path to the configuration file (overrides CONFIG_PATH)")
+}
+
+// ConfigPath returns the file read by FileStrategy: the --config flag if
+// bound and set, otherwise CONFIG_PATH, otherwise "config.yaml"
+func (l *Loader) ConfigPath() string {
+	if path := l.explicitConfigPath(); path != "" {
+		return path
+	}
+	return defaultConfigPath
+}
+
+// explicitConfigPath returns the file named by --config or CONFIG_PATH, or ""
+// if neither is set. HybridStrategy only reads a file named explicitly.
+func (l *Loader) explicitConfigPath() string {
+	if l.configFlag != "" {
+		return l.configFlag
+	}
+	return l.getenvDefault("CONFIG_PATH", "")
+}
+
+// BindFlags registers the --config flag of the manager's loader on fs; see
+// Loader.BindFlags
+func (m *Manager) BindFlags(fs *pflag.FlagSet) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.loader.BindFlags(fs)
+}
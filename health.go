@@ -0,0 +1,212 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultHealthCheckTimeout bounds each dependency check without its own timeout
+const defaultHealthCheckTimeout = 2 * time.Second
+
+// HealthCheckFunc checks a single dependency, returning nil if it is healthy.
+// It must return promptly once ctx is done.
+type HealthCheckFunc func(ctx context.Context) error
+
+// healthCheck is a registered custom dependency check
+type healthCheck struct {
+	timeout time.Duration
+	check   HealthCheckFunc
+}
+
+// AddHealthCheck registers a custom dependency check run by HealthCheck under
+// the given name, bounded by timeout (or the default of 2s if zero). A check
+// registered under the name of a built-in dependency replaces it.
+func (m *Manager) AddHealthCheck(name string, timeout time.Duration, check HealthCheckFunc) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.healthChecks == nil {
+		m.healthChecks = make(map[string]healthCheck)
+	}
+	m.healthChecks[name] = healthCheck{timeout: timeout, check: check}
+}
+
+// SetHealthCheckTimeout sets the timeout of a single dependency check, such
+// as "database", "redis" or "email"
+func (m *Manager) SetHealthCheckTimeout(name string, timeout time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.healthTimeouts == nil {
+		m.healthTimeouts = make(map[string]time.Duration)
+	}
+	m.healthTimeouts[name] = timeout
+}
+
+// HealthCheck checks every configured dependency concurrently and returns
+// the result of each, keyed by name, with nil meaning healthy. Built-in
+// checks open a TCP connection to the database ("database", or
+// "database_write" and "database_read" for read/write configurations),
+// Redis ("redis") and, if configured, the SMTP server ("email").
+//
+// Each dependency is bounded by its own timeout, so a hanging dependency is
+// reported with an error wrapping context.DeadlineExceeded without delaying
+// the others. HealthCheck returns once every check has finished or ctx is done.
+func (m *Manager) HealthCheck(ctx context.Context) map[string]error {
+	return runHealthChecks(ctx, m.healthChecksToRun())
+}
+
+// criticalDependencies names the built-in checks Ready requires to pass
+var criticalDependencies = []string{"database", "database_write", "database_read", "redis"}
+
+// Ready reports whether the service can take traffic: a configuration must
+// be loaded and valid, and the database and Redis must be reachable. It is
+// suitable for a /readyz handler; the returned error names every failure.
+func (m *Manager) Ready(ctx context.Context) error {
+	config := m.config.Load()
+	if config == nil {
+		return fmt.Errorf("not ready: no configuration loaded")
+	}
+	if err := m.validator.Validate(config); err != nil {
+		return fmt.Errorf("not ready: %w", err)
+	}
+	if err := m.checkCriticalDependencies(ctx); err != nil {
+		return fmt.Errorf("not ready: %w", err)
+	}
+	return nil
+}
+
+// SetValidateConnectivity controls whether ValidateCurrent (and IsValid)
+// also checks that the database and Redis accept TCP connections, each
+// bounded by its health check timeout. It is off by default.
+func (m *Manager) SetValidateConnectivity(enabled bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.validateConnectivity = enabled
+}
+
+// checkCriticalDependencies runs the built-in checks of the critical
+// dependencies and joins their failures, each prefixed with its name
+func (m *Manager) checkCriticalDependencies(ctx context.Context) error {
+	checks := m.healthChecksToRun()
+	critical := make(map[string]healthCheck, len(criticalDependencies))
+	for _, name := range criticalDependencies {
+		if hc, ok := checks[name]; ok {
+			critical[name] = hc
+		}
+	}
+
+	results := runHealthChecks(ctx, critical)
+	var failures []error
+	for _, name := range criticalDependencies {
+		if err := results[name]; err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(failures...)
+}
+
+// runHealthChecks runs checks concurrently and collects their results by name
+func runHealthChecks(ctx context.Context, checks map[string]healthCheck) map[string]error {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]error, len(checks))
+	)
+	for name, hc := range checks {
+		wg.Add(1)
+		go func(name string, hc healthCheck) {
+			defer wg.Done()
+			err := runHealthCheck(ctx, hc)
+
+			mu.Lock()
+			results[name] = err
+			mu.Unlock()
+		}(name, hc)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// healthChecksToRun returns the built-in checks for the current
+// configuration merged with the registered custom checks
+func (m *Manager) healthChecksToRun() map[string]healthCheck {
+	checks := make(map[string]healthCheck)
+
+	if m.IsLoaded() {
+		database := m.GetDatabaseConfig()
+		if m.IsReadWriteDatabase() {
+			checks["database_write"] = healthCheck{check: dialCheck(database.DBWriteHost, database.DBWritePort)}
+			checks["database_read"] = healthCheck{check: dialCheck(database.DBReadHost, database.DBReadPort)}
+		} else if database.DBType != "sqlite" {
+			checks["database"] = healthCheck{check: dialCheck(database.Host, database.Port)}
+		}
+
+		redis := m.GetResolvedRedisConfig()
+		checks["redis"] = healthCheck{check: dialCheck(redis.Host, redis.Port)}
+
+		if email := m.GetEmailConfig(); email.Host != "" {
+			checks["email"] = healthCheck{check: dialCheck(email.Host, strconv.Itoa(email.Port))}
+		}
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	for name, hc := range m.healthChecks {
+		checks[name] = hc
+	}
+	for name, timeout := range m.healthTimeouts {
+		if hc, ok := checks[name]; ok {
+			hc.timeout = timeout
+			checks[name] = hc
+		}
+	}
+	return checks
+}
+
+// runHealthCheck runs a single check within its timeout. A check that does
+// not return in time is abandoned and reported as timed out.
+func runHealthCheck(ctx context.Context, hc healthCheck) error {
+	timeout := hc.timeout
+	if timeout <= 0 {
+		timeout = defaultHealthCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- hc.check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil && ctx.Err() != nil {
+			return fmt.Errorf("health check timed out after %s: %w", timeout, ctx.Err())
+		}
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("health check timed out after %s: %w", timeout, ctx.Err())
+	}
+}
+
+// dialCheck returns a check that opens and closes a TCP connection to host:port
+func dialCheck(host, port string) HealthCheckFunc {
+	return func(ctx context.Context) error {
+		address := net.JoinHostPort(host, port)
+
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return fmt.Errorf("failed to connect to %s: %w", address, err)
+		}
+		return conn.Close()
+	}
+}
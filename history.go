@@ -0,0 +1,148 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// HistoryEntry records one installed configuration
+type HistoryEntry struct {
+	Time time.Time // when the configuration was installed
+
+	// Config is a copy of the installed configuration with secret values redacted
+	Config *Config
+
+	// Changes are the redacted differences from the previously installed
+	// configuration; nil for the initial load
+	Changes []FieldChange
+}
+
+// EnableHistory keeps the last max installed configurations for History.
+// Lowering max discards the oldest entries; a max of zero or less disables
+// history and discards every entry.
+func (m *Manager) EnableHistory(max int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if max <= 0 {
+		m.historyMax = 0
+		m.history = nil
+		return
+	}
+	m.historyMax = max
+	if len(m.history) > max {
+		m.history = m.history[:max]
+	}
+}
+
+// History returns the recorded configurations, newest first. It is empty
+// unless EnableHistory was called before the configurations were installed.
+func (m *Manager) History() []HistoryEntry {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return append([]HistoryEntry(nil), m.history...)
+}
+
+// recordHistory records newConfig, installed in place of oldConfig, if
+// history is enabled. The caller must hold m.mutex for writing.
+func (m *Manager) recordHistory(oldConfig, newConfig *Config) {
+	if m.historyMax == 0 {
+		return
+	}
+
+	entry := HistoryEntry{
+		Time:   time.Now(),
+		Config: redactConfig(newConfig),
+	}
+	if oldConfig != nil {
+		entry.Changes = RedactChanges(Diff(oldConfig, newConfig))
+	}
+
+	m.history = append([]HistoryEntry{entry}, m.history...)
+	if len(m.history) > m.historyMax {
+		m.history = m.history[:m.historyMax]
+	}
+}
+
+// redactConfig returns a deep copy of config with the values of secret
+// fields, including secret-looking keys of extensions, redacted
+func redactConfig(config *Config) *Config {
+	redacted := cloneConfig(config)
+	redacted.Extensions = redactExtensions(config.Extensions)
+
+	for _, field := range leafFields("", reflect.TypeOf(Config{})) {
+		if !isSecretField(field) {
+			continue
+		}
+
+		value, _ := lookupField(&redacted, field)
+		switch value.Kind() {
+		case reflect.String:
+			if value.String() != "" {
+				value.SetString(redactedValue)
+			}
+		case reflect.Slice:
+			if value.Type().Elem().Kind() != reflect.String || value.Len() == 0 {
+				continue
+			}
+			secrets := make([]string, value.Len())
+			for i := range secrets {
+				secrets[i] = redactedValue
+			}
+			value.Set(reflect.ValueOf(secrets))
+		}
+	}
+	return &redacted
+}
+
+// redactExtensions returns a copy of extensions with secret-looking keys
+// redacted, keeping a nil map nil
+func redactExtensions(extensions map[string]json.RawMessage) map[string]json.RawMessage {
+	if extensions == nil {
+		return nil
+	}
+	redacted := make(map[string]json.RawMessage, len(extensions))
+	for name, raw := range extensions {
+		redacted[name] = redactExtension(name, raw)
+	}
+	return redacted
+}
+
+// redactExtension redacts the values of secret-looking keys at any depth of
+// the named extension, or the whole extension if its name looks secret
+func redactExtension(name string, raw json.RawMessage) json.RawMessage {
+	redacted, _ := json.Marshal(redactedValue)
+
+	var value interface{}
+	if isSecretField(strings.ToLower(name)) || json.Unmarshal(raw, &value) != nil {
+		return redacted
+	}
+	data, err := json.Marshal(redactJSON(value))
+	if err != nil {
+		return redacted
+	}
+	return data
+}
+
+// redactJSON replaces, in place, the values of object keys that look secret
+// in a decoded JSON value
+func redactJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if isSecretField(strings.ToLower(key)) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactJSON(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactJSON(item)
+		}
+	}
+	return value
+}
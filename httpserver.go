@@ -0,0 +1,94 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// tlsVersions maps the accepted ServerConfig.MinTLSVersion values to their
+// crypto/tls constants
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// validTLSVersions lists the keys of tlsVersions in ascending order
+var validTLSVersions = []string{"1.0", "1.1", "1.2", "1.3"}
+
+// TLSEnabled reports whether the server is configured to serve TLS
+func (m *Manager) TLSEnabled() bool {
+	return tlsEnabled(m.GetServerConfig())
+}
+
+// GetTLSConfig returns the server TLS configuration with the certificate
+// loaded from the configured files and MinVersion set from
+// server.min_tls_version, or nil if TLS is not enabled
+func (m *Manager) GetTLSConfig() (*tls.Config, error) {
+	return serverTLSConfig(m.GetServerConfig())
+}
+
+// NewHTTPServer returns an *http.Server serving handler with its address,
+// timeouts, header limit and TLS configuration taken from the server
+// configuration. When TLS is enabled, start it with ListenAndServeTLS("", "").
+func (m *Manager) NewHTTPServer(handler http.Handler) (*http.Server, error) {
+	current := m.config.Load()
+	if current == nil {
+		return nil, fmt.Errorf("no configuration loaded")
+	}
+
+	config := current.Server
+	tlsConfig, err := serverTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Server{
+		Addr:           net.JoinHostPort(config.Host, config.Port),
+		Handler:        handler,
+		ReadTimeout:    config.ReadTimeout,
+		WriteTimeout:   config.WriteTimeout,
+		IdleTimeout:    config.IdleTimeout,
+		MaxHeaderBytes: config.MaxHeaderBytes,
+		TLSConfig:      tlsConfig,
+	}, nil
+}
+
+// tlsEnabled reports whether both TLS files are set
+func tlsEnabled(config ServerConfig) bool {
+	return config.TLSCertFile != "" && config.TLSKeyFile != ""
+}
+
+// serverTLSConfig loads the TLS certificate of config, returning nil if TLS is not enabled
+func serverTLSConfig(config ServerConfig) (*tls.Config, error) {
+	if !tlsEnabled(config) {
+		return nil, nil
+	}
+
+	minVersion, err := tlsVersion(config.MinTLSVersion)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	return &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: minVersion}, nil
+}
+
+// tlsVersion returns the crypto/tls constant for a MinTLSVersion value; an
+// empty value yields 0, which leaves crypto/tls's default in place
+func tlsVersion(version string) (uint16, error) {
+	version = strings.TrimSpace(version)
+	if version == "" {
+		return 0, nil
+	}
+	if v, ok := tlsVersions[version]; ok {
+		return v, nil
+	}
+	return 0, fmt.Errorf("unsupported TLS version %q: must be one of %s", version, strings.Join(validTLSVersions, ", "))
+}
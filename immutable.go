@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MarkImmutable marks fields that must not change once a configuration is
+// loaded, such as "jwt.issuer". A section name such as "jwt" covers every
+// field in that section. Loads, reloads, overrides and other updates that
+// would change a marked field fail with an error naming it, and the current
+// configuration is kept.
+func (m *Manager) MarkImmutable(paths ...string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.immutable = append(m.immutable, paths...)
+}
+
+// checkImmutable reports the immutable fields that differ between the
+// current configuration and its replacement. The caller must hold the lock.
+func (m *Manager) checkImmutable(current, next *Config) error {
+	if current == nil || len(m.immutable) == 0 {
+		return nil
+	}
+
+	changed := make([]string, 0)
+	for _, change := range Diff(current, next) {
+		for _, path := range m.immutable {
+			if matchesField(change.Field, path) {
+				changed = append(changed, change.Field)
+				break
+			}
+		}
+	}
+	if len(changed) > 0 {
+		return fmt.Errorf("immutable fields cannot change at runtime: %s", strings.Join(changed, ", "))
+	}
+	return nil
+}
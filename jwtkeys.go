@@ -0,0 +1,243 @@
+package config
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// defaultJWTAlgorithm is assumed when JWTConfig.Algorithm is empty
+const defaultJWTAlgorithm = "HS256"
+
+// jwtAlgorithms lists the supported signing algorithms
+var jwtAlgorithms = []string{"HS256", "HS384", "HS512", "RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+// jwtAlgorithmCurves maps each ECDSA algorithm to the curve it requires
+var jwtAlgorithmCurves = map[string]elliptic.Curve{
+	"ES256": elliptic.P256(),
+	"ES384": elliptic.P384(),
+	"ES512": elliptic.P521(),
+}
+
+// jwtKeys holds the keys parsed from one configuration
+type jwtKeys struct {
+	public  crypto.PublicKey
+	private crypto.PrivateKey
+}
+
+// GetJWTPublicKey returns the public key used to verify tokens signed with an
+// RS* or ES* algorithm: an *rsa.PublicKey or *ecdsa.PublicKey. It is read
+// from jwt.public_key_path, or derived from the private key when no public
+// key file is set. Keys are parsed once per loaded configuration.
+func (m *Manager) GetJWTPublicKey() (crypto.PublicKey, error) {
+	keys, err := m.loadJWTKeys()
+	if err != nil {
+		return nil, err
+	}
+	if keys.public == nil {
+		return nil, fmt.Errorf("no JWT public key configured: set jwt.public_key_path or jwt.private_key_path")
+	}
+	return keys.public, nil
+}
+
+// GetJWTPrivateKey returns the private key used to sign tokens with an RS* or
+// ES* algorithm: an *rsa.PrivateKey or *ecdsa.PrivateKey read from
+// jwt.private_key_path. Keys are parsed once per loaded configuration.
+func (m *Manager) GetJWTPrivateKey() (crypto.PrivateKey, error) {
+	keys, err := m.loadJWTKeys()
+	if err != nil {
+		return nil, err
+	}
+	if keys.private == nil {
+		return nil, fmt.Errorf("no JWT private key configured: set jwt.private_key_path")
+	}
+	return keys.private, nil
+}
+
+// loadJWTKeys returns the keys of the current configuration, parsing them
+// unless they were already parsed for it. Failures are not cached.
+func (m *Manager) loadJWTKeys() (jwtKeys, error) {
+	config := m.config.Load()
+	if config == nil {
+		return jwtKeys{}, fmt.Errorf("no configuration loaded")
+	}
+
+	m.jwtKeysMutex.Lock()
+	defer m.jwtKeysMutex.Unlock()
+
+	if m.jwtKeysConfig == config {
+		return m.jwtKeys, nil
+	}
+
+	keys, err := parseJWTKeys(config.JWT)
+	if err != nil {
+		return jwtKeys{}, err
+	}
+	m.jwtKeysConfig = config
+	m.jwtKeys = keys
+	return keys, nil
+}
+
+// parseJWTKeys reads the key files of config, checking that they suit its
+// algorithm and, when both are set, that they form a pair
+func parseJWTKeys(config JWTConfig) (jwtKeys, error) {
+	algorithm := jwtAlgorithm(config)
+	if !oneOf(algorithm, jwtAlgorithms) {
+		return jwtKeys{}, fmt.Errorf("unsupported JWT algorithm %q", config.Algorithm)
+	}
+	if strings.HasPrefix(algorithm, "HS") {
+		return jwtKeys{}, fmt.Errorf("JWT algorithm %s signs with the shared secret, not keys", algorithm)
+	}
+
+	var keys jwtKeys
+	if config.PrivateKeyPath != "" {
+		private, err := readJWTPrivateKey(config.PrivateKeyPath, algorithm)
+		if err != nil {
+			return jwtKeys{}, err
+		}
+		keys.private = private
+		keys.public = private.(crypto.Signer).Public()
+	}
+
+	if config.PublicKeyPath != "" {
+		public, err := readJWTPublicKey(config.PublicKeyPath, algorithm)
+		if err != nil {
+			return jwtKeys{}, err
+		}
+		if keys.private != nil && !jwtKeysMatch(keys.private, public) {
+			return jwtKeys{}, fmt.Errorf("JWT public key %s does not match private key %s", config.PublicKeyPath, config.PrivateKeyPath)
+		}
+		keys.public = public
+	}
+	return keys, nil
+}
+
+// jwtKeysMatch reports whether public is the public half of private
+func jwtKeysMatch(private crypto.PrivateKey, public crypto.PublicKey) bool {
+	derived, ok := private.(crypto.Signer).Public().(interface{ Equal(crypto.PublicKey) bool })
+	return ok && derived.Equal(public)
+}
+
+// jwtAlgorithm returns the configured algorithm in upper case, defaulting to HS256
+func jwtAlgorithm(config JWTConfig) string {
+	if config.Algorithm == "" {
+		return defaultJWTAlgorithm
+	}
+	return strings.ToUpper(config.Algorithm)
+}
+
+// jwtUsesSecret reports whether the configured algorithm signs with the
+// shared secret rather than a key pair
+func jwtUsesSecret(config JWTConfig) bool {
+	return strings.HasPrefix(jwtAlgorithm(config), "HS")
+}
+
+// readJWTPrivateKey parses the PEM private key at path. PKCS#1, PKCS#8 and
+// SEC 1 (EC) encodings are accepted.
+func readJWTPrivateKey(path, algorithm string) (crypto.PrivateKey, error) {
+	block, err := readPEMBlock(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var key crypto.PrivateKey
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("JWT private key %s: unexpected PEM block %q", path, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("JWT private key %s: %w", path, err)
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		err = checkJWTKeyAlgorithm(algorithm, &k.PublicKey)
+	case *ecdsa.PrivateKey:
+		err = checkJWTKeyAlgorithm(algorithm, &k.PublicKey)
+	default:
+		err = fmt.Errorf("unsupported key type %T", key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("JWT private key %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// readJWTPublicKey parses the PEM public key at path. PKIX and PKCS#1
+// encodings and certificates are accepted.
+func readJWTPublicKey(path, algorithm string) (crypto.PublicKey, error) {
+	block, err := readPEMBlock(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var key crypto.PublicKey
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("JWT public key %s: unexpected PEM block %q", path, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("JWT public key %s: %w", path, err)
+	}
+
+	if err := checkJWTKeyAlgorithm(algorithm, key); err != nil {
+		return nil, fmt.Errorf("JWT public key %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// checkJWTKeyAlgorithm reports whether key belongs to the family of
+// algorithm: RSA for RS*, ECDSA on the matching curve for ES*
+func checkJWTKeyAlgorithm(algorithm string, key crypto.PublicKey) error {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(algorithm, "RS") {
+			return fmt.Errorf("RSA key cannot be used with %s", algorithm)
+		}
+	case *ecdsa.PublicKey:
+		curve, ok := jwtAlgorithmCurves[algorithm]
+		if !ok {
+			return fmt.Errorf("ECDSA key cannot be used with %s", algorithm)
+		}
+		if k.Curve != curve {
+			return fmt.Errorf("%s requires a %s key, got %s", algorithm, curve.Params().Name, k.Curve.Params().Name)
+		}
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+	return nil
+}
+
+// readPEMBlock reads the first PEM block of the file at path
+func readPEMBlock(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("JWT key %s is not PEM encoded", path)
+	}
+	return block, nil
+}
@@ -1,8 +1,20 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"math"
 	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,180 +28,615 @@ const (
 	EnvironmentStrategy LoadStrategy = iota
 	FileStrategy
 	HybridStrategy
+	StdinStrategy
 )
 
 // Loader provides methods to load configuration
 type Loader struct {
-	viper *viper.Viper
+	viper      *viper.Viper
+	stdin      io.Reader
+	strict     bool
+	expandEnv  bool
+	migrations map[int]MigrationFunc
+	// envKeyReplacer maps config keys to environment variable names for
+	// viper's automatic environment binding
+	envKeyReplacer *strings.Replacer
+	// checkPermissions enables the file permission check in LoadFromFile
+	checkPermissions bool
+	// templating enables resolving Go templates in string fields after Load
+	templating bool
+	// secretProvider resolves "secret://" references after Load
+	secretProvider SecretProvider
+	// configFlag holds the value of the --config flag registered by BindFlags
+	configFlag string
+	// precedence is the merge order of HybridStrategy, if set explicitly
+	precedence []SourceKind
+	// fileOnly disables environment overrides of file keys while the
+	// hybrid file layer is loaded
+	fileOnly bool
+	// requiredEnv lists variables that LoadFromEnvironment requires to be set
+	requiredEnv []string
+	// warnings collects non-fatal issues found during the last load
+	warnings []string
+	// envErrors collects environment values that failed to parse during a load
+	envErrors []error
+	// loadedFiles lists the files read by the last load, in merge order
+	loadedFiles []string
+	// envRead records the set environment variables read by the last load,
+	// and envSnapshot replaces the process environment while a snapshot is
+	// replayed. loading is set while Load runs, so the file loads it makes
+	// keep recording into the same envRead.
+	envRead     map[string]string
+	envSnapshot map[string]string
+	loading     bool
+	// sources records which source produced each field of the last load
+	sources map[string]string
+	// conflicts records the fields set differently by several sources in
+	// the last hybrid load
+	conflicts []Conflict
+	// sectionSources holds the sources of sections mapped with MapSection
+	sectionSources map[string]SectionSource
+	// emptyEnv lists the variables LoadDotEnv set to a quoted empty value,
+	// which empty their fields instead of leaving them at the default
+	emptyEnv map[string]bool
 }
 
 // NewLoader creates a new configuration loader
 func NewLoader() *Loader {
-	v := viper.New()
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-	v.AutomaticEnv()
+	l := &Loader{
+		stdin:          os.Stdin,
+		expandEnv:      true,
+		envKeyReplacer: strings.NewReplacer(".", "_"),
+	}
+	l.resetViper()
+	return l
+}
+
+// resetViper replaces the viper instance so that no settings or config type
+// carry over from a previous load
+func (l *Loader) resetViper() {
+	l.warnings = nil
+	l.loadedFiles = nil
+	l.resetEnvRead()
+	l.viper = viper.New()
+}
+
+// SetStrict enables or disables strict mode. In strict mode, environment
+// values that cannot be parsed cause the load to fail instead of silently
+// falling back to their defaults, and file permission findings are errors.
+func (l *Loader) SetStrict(strict bool) {
+	l.strict = strict
+}
 
-	return &Loader{
-		viper: v,
+// SetEnvKeyReplacer sets the replacer that maps config keys to the
+// environment variables overriding them in file-based loads. The default maps
+// "." to "_", so "server.port" is overridden by SERVER_PORT; use, e.g.,
+// strings.NewReplacer(".", "_", "-", "_") to also support hyphenated keys.
+// A nil replacer uses keys unchanged.
+func (l *Loader) SetEnvKeyReplacer(replacer *strings.Replacer) {
+	if replacer == nil {
+		replacer = strings.NewReplacer()
 	}
+	l.envKeyReplacer = replacer
+	l.resetViper()
 }
 
-// LoadFromFile loads configuration from a file
+// RequireEnv makes LoadFromEnvironment fail with a precise error when any of
+// the named environment variables is unset or empty, instead of silently
+// applying its default (such as the placeholder JWT_SECRET)
+func (l *Loader) RequireEnv(names ...string) {
+	l.requiredEnv = append(l.requiredEnv, names...)
+}
+
+// SetExpandEnv enables or disables expansion of ${VAR} references in string
+// values loaded from files. Expansion is enabled by default. Only the braced
+// form is expanded; write "$$" for a literal "$" followed by "{".
+func (l *Loader) SetExpandEnv(enabled bool) {
+	l.expandEnv = enabled
+}
+
+// SetPermissionCheck enables or disables checking that config files holding
+// secrets are not readable by group or others. Violations are reported as
+// warnings, or as errors in strict mode. The check is disabled by default.
+func (l *Loader) SetPermissionCheck(enabled bool) {
+	l.checkPermissions = enabled
+}
+
+// Warnings returns the non-fatal issues found during the most recent load,
+// such as references to unset environment variables
+func (l *Loader) Warnings() []string {
+	return append([]string(nil), l.warnings...)
+}
+
+// LoadedFiles returns the absolute paths of the files read by the most recent
+// load, in the order they were merged. It is empty for loads that read no
+// files, such as environment-only or reader-based loads.
+func (l *Loader) LoadedFiles() []string {
+	return append([]string(nil), l.loadedFiles...)
+}
+
+// recordLoadedFile appends path, made absolute where possible, to loadedFiles
+func (l *Loader) recordLoadedFile(path string) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	l.loadedFiles = append(l.loadedFiles, path)
+}
+
+// LoadFromFile loads configuration from a file, detecting its format from the
+// extension: .yaml, .yml, .json or .toml
 func (l *Loader) LoadFromFile(configPath string) (*Config, error) {
-	l.viper.SetConfigFile(configPath)
+	return l.LoadFromFileWithFormat(configPath, "")
+}
 
-	if err := l.viper.ReadInConfig(); err != nil {
+// LoadFromFileWithFormat loads configuration from a file using an explicit
+// format ("yaml", "yml", "json" or "toml") instead of inferring it from the
+// file extension. This supports files such as "config" or "config.conf". An empty
+// format falls back to the extension.
+func (l *Loader) LoadFromFileWithFormat(configPath, format string) (*Config, error) {
+	if info, err := os.Stat(configPath); err == nil && info.IsDir() {
+		return nil, fmt.Errorf("config path is a directory, expected a file: %s (use LoadFromGlob, e.g. %q, to load every file in a directory)", configPath, filepath.Join(configPath, "*.yaml"))
+	}
+
+	l.resetViper()
+	if err := l.readConfigFile(configPath, format, false); err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
+	l.recordLoadedFile(configPath)
+
+	config, err := l.unmarshalConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if l.checkPermissions {
+		if err := l.checkFilePermissions(configPath, config); err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+// checkFilePermissions flags a config file that sets secret fields while
+// being readable by group or others
+func (l *Loader) checkFilePermissions(configPath string, config *Config) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat config file: %w", err)
+	}
+	mode := info.Mode().Perm()
+	if mode&0044 == 0 {
+		return nil
+	}
+
+	for _, field := range leafFields("", reflect.TypeOf(Config{})) {
+		if !isSecretField(field) || !l.viper.InConfig(field) {
+			continue
+		}
+		if value, ok := lookupField(config, field); ok && !value.IsZero() {
+			msg := fmt.Sprintf("config file %s contains secrets but is readable by group or others (mode %04o); restrict it with chmod 600", configPath, mode)
+			if l.strict {
+				return errors.New(msg)
+			}
+			l.warnings = append(l.warnings, msg)
+			return nil
+		}
+	}
+	return nil
+}
+
+// LoadFromGlob loads and merges every configuration file matching pattern,
+// e.g. "conf.d/*.yaml". Files are merged in lexical order, so values from
+// later files override earlier ones.
+func (l *Loader) LoadFromGlob(pattern string) (*Config, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config glob %q: %w", pattern, err)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no config files match pattern %q", pattern)
+	}
+	sort.Strings(matches)
+
+	l.resetViper()
+	for i, path := range matches {
+		if err := l.readConfigFile(path, "", i > 0); err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
+		l.recordLoadedFile(path)
+	}
+
+	return l.unmarshalConfig()
+}
+
+// LoadEnvironmentFile loads dir/<env>.yaml, where env is APP_ENVIRONMENT
+// (default "development"), falling back to dir/default.yaml if no file
+// exists for that environment
+func (l *Loader) LoadEnvironmentFile(dir string) (*Config, error) {
+	env := strings.ToLower(l.getenvDefault("APP_ENVIRONMENT", bindingDefault("app.environment")))
+
+	configPath := filepath.Join(dir, env+".yaml")
+	if _, err := os.Stat(configPath); errors.Is(err, fs.ErrNotExist) {
+		configPath = filepath.Join(dir, "default.yaml")
+		if _, err := os.Stat(configPath); errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("no config file for environment %q in %s (looked for %s.yaml and default.yaml)", env, dir, env)
+		}
+	}
+
+	return l.LoadFromFile(configPath)
+}
+
+// LoadFromReader loads configuration in the given format, "yaml", "yml",
+// "json" or "toml", from r
+func (l *Loader) LoadFromReader(r io.Reader, format string) (*Config, error) {
+	format, err := configFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	l.resetViper()
+	l.viper.SetConfigType(format)
+
+	data, err := io.ReadAll(r)
+	if err == nil {
+		data, err = configText(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s config: %w", format, err)
+	}
+	if err := l.viper.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("failed to read %s config: %w", format, err)
+	}
+
+	return l.unmarshalConfig()
+}
+
+// LoadFromFS loads configuration from the file at name within fsys, such as
+// an embed.FS holding a default config. The format is inferred from the file
+// extension, which must name a supported format as for LoadFromFile.
+func (l *Loader) LoadFromFS(fsys fs.FS, name string) (*Config, error) {
+	format := strings.TrimPrefix(path.Ext(name), ".")
+	if format == "" {
+		return nil, fmt.Errorf("cannot infer config format of %s: missing file extension", name)
+	}
+
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	return l.LoadFromReader(bytes.NewReader(data), format)
+}
+
+// SetStdin replaces the reader used by LoadFromStdin, which defaults to os.Stdin
+func (l *Loader) SetStdin(r io.Reader) {
+	l.stdin = r
+}
+
+// LoadFromBytes loads configuration in the given format from data, such as
+// a document fetched from a secret store or an API
+func (l *Loader) LoadFromBytes(data []byte, format string) (*Config, error) {
+	return l.LoadFromReader(bytes.NewReader(data), format)
+}
+
+// LoadFromStdin loads configuration in the given format from standard input
+func (l *Loader) LoadFromStdin(format string) (*Config, error) {
+	return l.LoadFromReader(l.stdin, format)
+}
+
+// unmarshalConfig migrates and decodes the settings currently held by viper
+func (l *Loader) unmarshalConfig() (*Config, error) {
+	if err := l.migrate(); err != nil {
+		return nil, err
+	}
+	l.applySectionDefaults()
+	if !l.fileOnly {
+		l.applyEnvOverrides()
+	}
 
 	var config Config
-	if err := l.viper.Unmarshal(&config); err != nil {
+	if err := l.viper.Unmarshal(&config, l.decodeHooks()); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	l.recordFileSources()
 
 	return &config, nil
 }
 
+// applyEnvOverrides overrides every key viper holds, from the file or section
+// defaults, with the environment variable named by the key replacer, e.g.
+// SERVER_PORT for server.port. Variables are read through getenv so that
+// snapshot replays apply to file loads too.
+func (l *Loader) applyEnvOverrides() {
+	for _, key := range l.viper.AllKeys() {
+		if value := l.getenv(l.envKeyName(key)); value != "" {
+			l.viper.Set(key, value)
+		}
+	}
+}
+
+// envKeyName returns the environment variable overriding the config key
+func (l *Loader) envKeyName(key string) string {
+	return strings.ToUpper(l.envKeyReplacer.Replace(key))
+}
+
+// applySectionDefaults fills the missing fields of every section that the
+// loaded file specifies only partially. Sections absent from the file are
+// left untouched.
+func (l *Loader) applySectionDefaults() {
+	for _, binding := range envBindings {
+		section := strings.SplitN(binding.Field, ".", 2)[0]
+		if l.viper.InConfig(section) && !l.viper.InConfig(binding.Field) {
+			l.viper.SetDefault(binding.Field, binding.Default)
+		}
+	}
+}
+
 // LoadFromEnvironment loads configuration from environment variables
 func (l *Loader) LoadFromEnvironment() (*Config, error) {
-	config := &Config{
-		Server: ServerConfig{
-			Port:         getEnv("SERVER_PORT", "8080"),
-			Host:         getEnv("SERVER_HOST", "0.0.0.0"),
-			ReadTimeout:  getDurationEnv("SERVER_READ_TIMEOUT", 30*time.Second),
-			WriteTimeout: getDurationEnv("SERVER_WRITE_TIMEOUT", 30*time.Second),
-			IdleTimeout:  getDurationEnv("SERVER_IDLE_TIMEOUT", 60*time.Second),
-		},
-		Database: DatabaseConfig{
-			// Read/Write Database Configuration
-			DBWriteHost:     getEnv("DB_WRITE_HOST", ""),
-			DBWritePort:     getEnv("DB_WRITE_PORT", "5432"),
-			DBWriteUser:     getEnv("DB_WRITE_USER", ""),
-			DBWritePassword: getEnv("DB_WRITE_PASSWORD", ""),
-			DBWriteName:     getEnv("DB_WRITE_NAME", ""),
-
-			DBReadHost:     getEnv("DB_READ_HOST", ""),
-			DBReadPort:     getEnv("DB_READ_PORT", "5432"),
-			DBReadUser:     getEnv("DB_READ_USER", ""),
-			DBReadPassword: getEnv("DB_READ_PASSWORD", ""),
-			DBReadName:     getEnv("DB_READ_NAME", ""),
-
-			// Legacy Database Configuration (Backward Compatibility)
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "5432"),
-			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", ""),
-			DBName:   getEnv("DB_NAME", "app"),
-
-			// Database Type and Environment
-			SSLMode:            getEnv("DB_SSL_MODE", "disable"),
-			MaxConns:           getIntEnv("DB_MAX_CONNS", 10),
-			DBType:             getEnv("DB_TYPE", "postgresql"),
-			Environment:        getEnv("APP_ENVIRONMENT", "development"),
-			DatabaseConfigType: getEnv("DATABASE_CONFIG_TYPE", "auto_detect"),
-		},
-		Redis: RedisConfig{
-			Host:     getEnv("REDIS_HOST", "localhost"),
-			Port:     getEnv("REDIS_PORT", "6379"),
-			Password: getEnv("REDIS_PASSWORD", ""),
-			DB:       getIntEnv("REDIS_DB", 0),
-		},
-		Log: LogConfig{
-			Level:      getEnv("LOG_LEVEL", "info"),
-			Format:     getEnv("LOG_FORMAT", "json"),
-			OutputPath: getEnv("LOG_OUTPUT_PATH", ""),
-		},
-		JWT: JWTConfig{
-			Secret:     getEnv("JWT_SECRET", "your-secret-key"),
-			Expiration: getDurationEnv("JWT_EXPIRATION", 24*time.Hour),
-			Issuer:     getEnv("JWT_ISSUER", "app"),
-		},
-		Email: EmailConfig{
-			Host:     getEnv("EMAIL_HOST", ""),
-			Port:     getIntEnv("EMAIL_PORT", 587),
-			Username: getEnv("EMAIL_USERNAME", ""),
-			Password: getEnv("EMAIL_PASSWORD", ""),
-			From:     getEnv("EMAIL_FROM", ""),
-		},
-		App: AppConfig{
-			Name:        getEnv("APP_NAME", "app"),
-			Environment: getEnv("APP_ENVIRONMENT", "development"),
-			Version:     getEnv("APP_VERSION", "1.0.0"),
-			Debug:       getBoolEnv("APP_DEBUG", false),
-		},
+	l.envErrors = nil
+	l.warnings = nil
+	l.loadedFiles = nil
+	l.resetEnvRead()
+
+	if err := l.checkRequiredEnv(); err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	sources := defaultSources()
+	for _, binding := range envBindings {
+		fromEnv, err := l.applyEnvBinding(config, binding)
+		if err != nil {
+			return nil, err
+		}
+		if fromEnv {
+			sources[binding.Field] = SourceEnv
+		}
+	}
+	if l.loadOperationTimeouts(config) {
+		sources["server.timeouts"] = SourceEnv
+	}
+	l.sources = sources
+
+	if err := l.strictEnvError(); err != nil {
+		return nil, err
 	}
 
 	return config, nil
 }
 
-// Load loads configuration using the specified strategy
+// checkRequiredEnv reports the required environment variables that are not set
+func (l *Loader) checkRequiredEnv() error {
+	missing := make([]string, 0)
+	for _, name := range l.requiredEnv {
+		if value, _ := l.bindingEnv(name); value == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("required environment variables not set: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// strictEnvError returns the collected environment parse errors in strict mode
+func (l *Loader) strictEnvError() error {
+	if l.strict && len(l.envErrors) > 0 {
+		return fmt.Errorf("invalid environment configuration: %w", errors.Join(l.envErrors...))
+	}
+	return nil
+}
+
+// envFromSuffix marks a variable that names another variable holding the
+// value, e.g. DB_PASSWORD_FROM=SECRET_DB_PW
+const envFromSuffix = "_FROM"
+
+// bindingEnv returns the value of the named variable. When it is unset,
+// <name>_FROM may name another variable to read the value from instead; an
+// error is returned if that variable is unset too.
+func (l *Loader) bindingEnv(name string) (string, error) {
+	if value := l.getenv(name); value != "" {
+		return value, nil
+	}
+
+	target := l.getenv(name + envFromSuffix)
+	if target == "" {
+		return "", nil
+	}
+	value := l.getenv(target)
+	if value == "" {
+		return "", fmt.Errorf("%s%s names %s, which is not set", name, envFromSuffix, target)
+	}
+	return value, nil
+}
+
+// applyEnvBinding sets a single field from its environment variable, falling
+// back to the binding's default when the variable is unset or unparsable.
+// It reports whether the value came from the environment.
+func (l *Loader) applyEnvBinding(config *Config, binding envBinding) (bool, error) {
+	value, err := l.bindingEnv(binding.Name)
+	if err != nil {
+		l.envErrors = append(l.envErrors, err)
+	}
+	if value != "" {
+		err = setFieldFromString(config, binding.Field, value)
+		if err == nil {
+			return true, nil
+		}
+		l.envErrors = append(l.envErrors, fmt.Errorf("%s: %w", binding.Name, err))
+	}
+	if value == "" && err == nil && l.setExplicitlyEmpty(config, binding) {
+		return true, nil
+	}
+
+	if err := setFieldFromString(config, binding.Field, binding.Default); err != nil {
+		return false, fmt.Errorf("invalid default for %s: %w", binding.Name, err)
+	}
+	return false, nil
+}
+
+// Load loads configuration using the specified strategy, then resolves
+// secret references and, if templating is enabled, templated fields
 func (l *Loader) Load(strategy LoadStrategy) (*Config, error) {
+	config, err := l.loadStrategy(strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.applySectionSources(config); err != nil {
+		return nil, err
+	}
+	secrets, err := l.resolveSecrets(config)
+	if err != nil {
+		return nil, err
+	}
+	if l.templating {
+		if err := resolveTemplates(config, secrets); err != nil {
+			return nil, err
+		}
+	}
+	normalize(config)
+	return config, nil
+}
+
+// LoadAndValidate loads configuration with strategy and validates it, like
+// Manager.Load but without a Manager, for one-shot tools that need neither
+// watchers nor reloads. It returns the validated configuration.
+func LoadAndValidate(strategy LoadStrategy) (*Config, error) {
+	loader := NewLoader()
+	config, err := loader.Load(strategy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if _, err := NewValidator().validateWithWarnings(config, loader.FieldSources()); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+	return config, nil
+}
+
+// loadStrategy dispatches to the loader for strategy
+func (l *Loader) loadStrategy(strategy LoadStrategy) (*Config, error) {
+	l.conflicts = nil
+	l.envRead = make(map[string]string)
+	l.loading = true
+	defer func() { l.loading = false }()
+
 	switch strategy {
 	case FileStrategy:
-		configPath := getEnv("CONFIG_PATH", "config.yaml")
-		return l.LoadFromFile(configPath)
+		return l.LoadFromFile(l.ConfigPath())
 	case EnvironmentStrategy:
 		return l.LoadFromEnvironment()
+	case StdinStrategy:
+		return l.LoadFromStdin(l.getenvDefault("CONFIG_FORMAT", "yaml"))
 	case HybridStrategy:
-		// Try file first, fallback to environment
-		if configPath := getEnv("CONFIG_PATH", ""); configPath != "" {
-			if config, err := l.LoadFromFile(configPath); err == nil {
-				return config, nil
-			}
-		}
-		return l.LoadFromEnvironment()
+		// Layer defaults, then the file (if any), then explicitly-set environment variables
+		return l.loadHybrid()
 	default:
 		return l.LoadFromEnvironment()
 	}
 }
 
+// LoadInto resolves configuration with the given strategy, exactly as Load
+// does, and decodes the result into out, which must be a pointer to a struct.
+// Fields of out are matched by mapstructure tag against the fields of Config,
+// so custom structs can declare only the sections and fields they need.
+func (l *Loader) LoadInto(strategy LoadStrategy, out interface{}) error {
+	config, err := l.Load(strategy)
+	if err != nil {
+		return err
+	}
+	return decodeInto(config, out)
+}
+
+// LoadSection resolves configuration with the given strategy, exactly as
+// Load does, and decodes only the section at the dotted path section (e.g.
+// "database" or "server.timeouts") into out, which must be a pointer. Fields
+// are matched by mapstructure tag, so out may be the section's own type,
+// such as *RedisConfig, or a custom struct declaring only what it needs.
+func (l *Loader) LoadSection(strategy LoadStrategy, section string, out interface{}) error {
+	config, err := l.Load(strategy)
+	if err != nil {
+		return err
+	}
+
+	value, ok := lookupField(config, section)
+	if !ok {
+		return fmt.Errorf("unknown config section %q", section)
+	}
+	if value.Kind() == reflect.Struct {
+		return decodeValue(configMap(value), out)
+	}
+	return decodeValue(value.Interface(), out)
+}
+
 // Helper functions for environment variable handling
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
+func (l *Loader) getenvDefault(key, defaultValue string) string {
+	if value := l.getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
 
-func getIntEnv(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := parseInt(value); err == nil {
-			return intValue
-		}
+// Parse functions
+
+// parseInt parses a base-10 integer, rejecting trailing garbage such as "123abc"
+func parseInt(s string) (int, error) {
+	i, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer value: %s", s)
 	}
-	return defaultValue
+	return i, nil
 }
 
-func getBoolEnv(key string, defaultValue bool) bool {
-	if value := os.Getenv(key); value != "" {
-		if boolValue, err := parseBool(value); err == nil {
-			return boolValue
+// dayUnitPattern matches day components such as "7d" or "1.5d" in a duration string
+var dayUnitPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)d`)
+
+// parseDuration parses a duration like time.ParseDuration, additionally
+// accepting a "d" (day) unit, e.g. "7d", "30d" or "1d12h". Fractional days
+// are converted exactly, without floating-point rounding.
+func parseDuration(s string) (time.Duration, error) {
+	var convErr error
+	expanded := dayUnitPattern.ReplaceAllStringFunc(s, func(match string) string {
+		// A day count read as hours, times 24, keeps ParseDuration's exact decimal arithmetic
+		hours, err := time.ParseDuration(strings.TrimSuffix(match, "d") + "h")
+		if err == nil && hours > math.MaxInt64/24 {
+			err = fmt.Errorf("%s overflows", match)
 		}
+		if err != nil {
+			convErr = err
+			return match
+		}
+		return strconv.FormatInt(int64(hours*24), 10) + "ns"
+	})
+	if convErr != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", s, convErr)
 	}
-	return defaultValue
+	return time.ParseDuration(expanded)
 }
 
-func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
-	if value := os.Getenv(key); value != "" {
-		if duration, err := time.ParseDuration(value); err == nil {
-			return duration
+// parseList splits a comma-separated list, trimming spaces and dropping empty
+// entries. An empty string yields a nil list.
+func parseList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
 		}
 	}
-	return defaultValue
-}
-
-// Parse functions
-func parseInt(s string) (int, error) {
-	var i int
-	_, err := fmt.Sscanf(s, "%d", &i)
-	return i, err
+	return items
 }
 
 func parseBool(s string) (bool, error) {
 	switch strings.ToLower(s) {
-	case "true", "1", "yes", "on":
+	case "true", "1", "yes", "y", "on", "enabled":
 		return true, nil
-	case "false", "0", "no", "off":
+	case "false", "0", "no", "n", "off", "disabled":
 		return false, nil
 	default:
 		return false, fmt.Errorf("invalid boolean value: %s", s)
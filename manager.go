@@ -1,24 +1,109 @@
 package config
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Manager provides a high-level interface for configuration management
 type Manager struct {
-	config    *Config
+	// config holds the current configuration. It is only stored while mutex
+	// is held for writing, but is read lock-free by the accessors.
+	config    atomic.Pointer[Config]
 	loader    *Loader
 	validator *Validator
 	mutex     sync.RWMutex
 	watchers  []ConfigWatcher
+	sources   map[string]string
+	conflicts []Conflict
+
+	// loadWarnings and validationWarnings hold the loader and validator
+	// warnings for the current configuration
+	loadWarnings       []string
+	validationWarnings []string
+
+	// immutable holds the dotted paths that must not change once loaded
+	immutable []string
+
+	// validateConnectivity makes ValidateCurrent check dependency reachability
+	validateConnectivity bool
+
+	// syncNotify runs watchers on the loading goroutine instead of in the background
+	syncNotify bool
+
+	// overrides pins fields to operator-set values across loads, keyed by dotted path
+	overrides map[string]interface{}
+
+	// healthChecks holds custom dependency checks and healthTimeouts
+	// per-dependency timeouts, both keyed by dependency name
+	healthChecks   map[string]healthCheck
+	healthTimeouts map[string]time.Duration
+
+	// jwtKeys caches the JWT keys parsed for jwtKeysConfig
+	jwtKeysMutex  sync.Mutex
+	jwtKeysConfig *Config
+	jwtKeys       jwtKeys
+
+	// history holds the last historyMax installed configurations, newest first
+	history    []HistoryEntry
+	historyMax int
+
+	subscribers      map[int]chan *Config
+	nextSubscriberID int
+
+	lastLoadTime   time.Time
+	lastReloadTime time.Time
 }
 
+// subscriberBufferSize is the number of pending changes buffered per subscriber
+const subscriberBufferSize = 8
+
 // ConfigWatcher defines an interface for configuration change watchers
 type ConfigWatcher interface {
 	OnConfigChanged(oldConfig, newConfig *Config)
 }
 
+// ConfigWatcherWithError is a configuration change watcher that reports
+// whether it managed to apply the change, e.g. by reopening a connection pool
+type ConfigWatcherWithError interface {
+	OnConfigChanged(oldConfig, newConfig *Config) error
+}
+
+// WatcherError is returned when a configuration was installed but one or
+// more watchers registered with AddWatcherWithError failed to apply it. It is
+// only reported in synchronous notification mode.
+type WatcherError struct {
+	Errors []error
+}
+
+func (e *WatcherError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("configuration changed but %d watcher(s) failed to apply it: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the individual watcher errors for errors.Is and errors.As
+func (e *WatcherError) Unwrap() []error {
+	return e.Errors
+}
+
+// isWatcherError reports whether err only signals failed watchers, meaning
+// the new configuration itself was installed
+func isWatcherError(err error) bool {
+	var watcherErr *WatcherError
+	return errors.As(err, &watcherErr)
+}
+
 // NewManager creates a new configuration manager
 func NewManager() *Manager {
 	return &Manager{
@@ -28,115 +113,341 @@ func NewManager() *Manager {
 	}
 }
 
-// Load loads and validates configuration using the specified strategy
+// Load loads and validates configuration using the specified strategy.
+// The new configuration is only installed once it has loaded and validated
+// successfully; on any error the previous configuration, field sources and
+// watcher state are left untouched and no watchers are notified.
 func (m *Manager) Load(strategy LoadStrategy) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
+	return m.loadLocked(strategy, false)
+}
 
+// loadLocked implements Load and Reload; the caller must hold mutex for
+// writing. A reload also stamps lastReloadTime with the time of the install.
+func (m *Manager) loadLocked(strategy LoadStrategy, reload bool) error {
 	config, err := m.loader.Load(strategy)
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
+	sources := m.loader.FieldSources()
+	if err := m.applyOverrides(config, sources); err != nil {
+		return err
+	}
+	normalize(config)
 
 	// Validate the configuration
-	if err := m.validator.Validate(config); err != nil {
+	warnings, err := m.validator.validateWithWarnings(config, sources)
+	if err != nil {
 		return fmt.Errorf("configuration validation failed: %w", err)
 	}
 
 	// Store the old config for watchers
-	oldConfig := m.config
-	m.config = config
+	oldConfig := m.config.Load()
+	if err := m.checkImmutable(oldConfig, config); err != nil {
+		return err
+	}
+	m.config.Store(config)
+	m.recordHistory(oldConfig, config)
+	m.sources = sources
+	m.conflicts = m.loader.Conflicts()
+	m.loadWarnings = m.loader.Warnings()
+	m.validationWarnings = warnings
+	m.lastLoadTime = time.Now()
+	if reload {
+		m.lastReloadTime = m.lastLoadTime
+	}
 
 	// Notify watchers if this is not the initial load
 	if oldConfig != nil {
-		m.notifyWatchers(oldConfig, config)
+		return m.notifyWatchers(oldConfig, config)
+	}
+
+	return nil
+}
+
+// LoadConfig validates and installs a caller-provided configuration, bypassing
+// the environment and files entirely. The configuration is copied, so later
+// changes by the caller do not affect the manager. As with Load, watchers are
+// notified unless this is the initial load, and nothing changes on error.
+func (m *Manager) LoadConfig(c *Config) error {
+	if c == nil {
+		return fmt.Errorf("configuration must not be nil")
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	config := cloneConfig(c)
+	sources := memorySources()
+	if err := m.applyOverrides(&config, sources); err != nil {
+		return err
+	}
+	normalize(&config)
+	warnings, err := m.validator.validateWithWarnings(&config, sources)
+	if err != nil {
+		return fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	oldConfig := m.config.Load()
+	if err := m.checkImmutable(oldConfig, &config); err != nil {
+		return err
+	}
+	m.config.Store(&config)
+	m.recordHistory(oldConfig, &config)
+	m.sources = sources
+	m.conflicts = nil
+	m.loadWarnings = nil
+	m.validationWarnings = warnings
+	m.lastLoadTime = time.Now()
+
+	if oldConfig != nil {
+		return m.notifyWatchers(oldConfig, &config)
 	}
 
 	return nil
 }
 
-// GetConfig returns the current configuration (thread-safe)
+// cloneConfig returns a deep copy of c, so that the maps and slices it holds
+// are not shared with the caller
+func cloneConfig(c *Config) Config {
+	config := *c
+	config.Database.ReadReplicas = cloneStrings(c.Database.ReadReplicas)
+	config.JWT.PreviousSecrets = cloneStrings(c.JWT.PreviousSecrets)
+	config.Email.AllowedFromDomains = cloneStrings(c.Email.AllowedFromDomains)
+
+	if c.Server.Timeouts != nil {
+		config.Server.Timeouts = make(map[string]time.Duration, len(c.Server.Timeouts))
+		for name, timeout := range c.Server.Timeouts {
+			config.Server.Timeouts[name] = timeout
+		}
+	}
+	if c.Extensions != nil {
+		config.Extensions = make(map[string]json.RawMessage, len(c.Extensions))
+		for name, raw := range c.Extensions {
+			config.Extensions[name] = append(json.RawMessage(nil), raw...)
+		}
+	}
+	return config
+}
+
+// cloneStrings copies values, keeping a nil slice nil
+func cloneStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(values)), values...)
+}
+
+// GetConfig returns the current configuration (thread-safe, lock-free)
 func (m *Manager) GetConfig() *Config {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
-	return m.config
+	return m.config.Load()
 }
 
 // GetServerConfig returns the server configuration
 func (m *Manager) GetServerConfig() ServerConfig {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
-	if m.config == nil {
+	config := m.config.Load()
+	if config == nil {
 		return ServerConfig{}
 	}
-	return m.config.Server
+	return config.Server
+}
+
+// GetOperationTimeout returns the named per-operation timeout, if configured
+func (m *Manager) GetOperationTimeout(name string) (time.Duration, bool) {
+	config := m.GetServerConfig()
+	timeout, ok := config.Timeouts[strings.ToLower(name)]
+	return timeout, ok
+}
+
+// UnmarshalExtension decodes the named section of the "extensions:" block into out
+func (m *Manager) UnmarshalExtension(name string, out interface{}) error {
+	config := m.GetConfig()
+	if config == nil {
+		return fmt.Errorf("no configuration loaded")
+	}
+
+	raw, ok := config.Extensions[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("extension %q not found", name)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("failed to unmarshal extension %q: %w", name, err)
+	}
+	return nil
 }
 
 // GetDatabaseConfig returns the database configuration
 func (m *Manager) GetDatabaseConfig() DatabaseConfig {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
-	if m.config == nil {
+	config := m.config.Load()
+	if config == nil {
 		return DatabaseConfig{}
 	}
-	return m.config.Database
+	return config.Database
 }
 
 // GetRedisConfig returns the Redis configuration
 func (m *Manager) GetRedisConfig() RedisConfig {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
-	if m.config == nil {
+	config := m.config.Load()
+	if config == nil {
 		return RedisConfig{}
 	}
-	return m.config.Redis
+	return config.Redis
 }
 
 // GetLogConfig returns the logging configuration
 func (m *Manager) GetLogConfig() LogConfig {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
-	if m.config == nil {
+	config := m.config.Load()
+	if config == nil {
 		return LogConfig{}
 	}
-	return m.config.Log
+	return config.Log
 }
 
 // GetJWTConfig returns the JWT configuration
 func (m *Manager) GetJWTConfig() JWTConfig {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
-	if m.config == nil {
+	config := m.config.Load()
+	if config == nil {
 		return JWTConfig{}
 	}
-	return m.config.JWT
+	return config.JWT
 }
 
 // GetEmailConfig returns the email configuration
 func (m *Manager) GetEmailConfig() EmailConfig {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
-	if m.config == nil {
+	config := m.config.Load()
+	if config == nil {
 		return EmailConfig{}
 	}
-	return m.config.Email
+	return config.Email
 }
 
 // GetAppConfig returns the application configuration
 func (m *Manager) GetAppConfig() AppConfig {
+	config := m.config.Load()
+	if config == nil {
+		return AppConfig{}
+	}
+	return config.App
+}
+
+// GetRuntimeConfig returns the Go runtime configuration
+func (m *Manager) GetRuntimeConfig() RuntimeConfig {
+	config := m.config.Load()
+	if config == nil {
+		return RuntimeConfig{}
+	}
+	return config.Runtime
+}
+
+// Equal reports whether m and other hold equal configurations. Two managers
+// without a loaded configuration are equal; a loaded and an unloaded one are
+// not. A nil manager is treated as unloaded.
+func (m *Manager) Equal(other *Manager) bool {
+	var mine, theirs *Config
+	if m != nil {
+		mine = m.GetConfig()
+	}
+	if other != nil {
+		theirs = other.GetConfig()
+	}
+	return mine.Equal(theirs)
+}
+
+// FieldSources returns the source ("default", "file", "env", "memory" or
+// "override") that produced each field of the current configuration, keyed by
+// dotted field path
+func (m *Manager) FieldSources() map[string]string {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
-	if m.config == nil {
-		return AppConfig{}
+
+	sources := make(map[string]string, len(m.sources))
+	for field, source := range m.sources {
+		sources[field] = source
 	}
-	return m.config.App
+	return sources
 }
 
-// AddWatcher adds a configuration change watcher
+// AddWatcher adds a configuration change watcher with priority 0
 func (m *Manager) AddWatcher(watcher ConfigWatcher) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
-	m.watchers = append(m.watchers, watcher)
+	m.insertWatcherLocked(watcher)
+}
+
+// AddWatcherWithPriority adds a watcher that, in synchronous notification
+// mode, runs before every watcher with a lower priority, e.g. to reconfigure
+// logging before reopening database connections. Watchers added without a
+// priority have priority 0, and watchers of equal priority run in
+// registration order. Asynchronous notification does not guarantee order.
+func (m *Manager) AddWatcherWithPriority(watcher ConfigWatcher, priority int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.insertWatcherLocked(&priorityWatcher{watcher: watcher, priority: priority})
+}
+
+// insertWatcherLocked adds watcher after every watcher of the same or higher
+// priority, keeping m.watchers in notification order. The caller must hold the lock.
+func (m *Manager) insertWatcherLocked(watcher ConfigWatcher) {
+	priority := watcherPriority(watcher)
+	i := len(m.watchers)
+	for i > 0 && watcherPriority(m.watchers[i-1]) < priority {
+		i--
+	}
+	m.watchers = append(m.watchers, nil)
+	copy(m.watchers[i+1:], m.watchers[i:])
+	m.watchers[i] = watcher
+}
+
+// AddWatcherWithError adds a watcher that can report failing to apply a
+// change. Its errors are returned by the loading call, such as Reload, in
+// synchronous notification mode and discarded otherwise.
+func (m *Manager) AddWatcherWithError(watcher ConfigWatcherWithError) {
+	m.AddWatcher(&errorWatcher{watcher: watcher})
+}
+
+// RemoveWatcherWithError removes a watcher added with AddWatcherWithError
+func (m *Manager) RemoveWatcherWithError(watcher ConfigWatcherWithError) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for i, w := range m.watchers {
+		if ew, ok := w.(*errorWatcher); ok && ew.watcher == watcher {
+			m.watchers = append(m.watchers[:i], m.watchers[i+1:]...)
+			break
+		}
+	}
+}
+
+// SetSynchronousNotify controls how watchers are notified. By default each
+// watcher runs in its own goroutine. In synchronous mode watchers run in
+// priority order, then registration order, before Load, Reload or an update returns, and errors
+// from watchers added with AddWatcherWithError are returned as a
+// *WatcherError. Synchronous watchers run while the manager is locked, so
+// they must only use its lock-free accessors such as GetConfig.
+func (m *Manager) SetSynchronousNotify(enabled bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.syncNotify = enabled
+}
+
+// Validator returns the validator used by Load, Reload, LoadConfig and
+// ValidateCurrent, so checks such as SetStrict or RequireInEnvironment can be
+// configured for the manager. Settings apply from the next validation.
+func (m *Manager) Validator() *Validator {
+	return m.validator
+}
+
+// AddWatcherFor adds a watcher that is only notified when one of the given
+// fields changed. Fields are dotted paths such as "database.host"; a section
+// name such as "database" matches every field in that section.
+func (m *Manager) AddWatcherFor(fields []string, watcher ConfigWatcher) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.insertWatcherLocked(&fieldWatcher{
+		fields:  append([]string(nil), fields...),
+		watcher: watcher,
+	})
 }
 
 // RemoveWatcher removes a configuration change watcher
@@ -149,109 +460,555 @@ func (m *Manager) RemoveWatcher(watcher ConfigWatcher) {
 			m.watchers = append(m.watchers[:i], m.watchers[i+1:]...)
 			break
 		}
+		if fw, ok := w.(*fieldWatcher); ok && fw.watcher == watcher {
+			m.watchers = append(m.watchers[:i], m.watchers[i+1:]...)
+			break
+		}
+		if pw, ok := w.(*priorityWatcher); ok && pw.watcher == watcher {
+			m.watchers = append(m.watchers[:i], m.watchers[i+1:]...)
+			break
+		}
+	}
+}
+
+// Subscribe returns a channel that receives the new configuration after each
+// change, plus a function that unsubscribes and closes the channel. The
+// channel is buffered; if a subscriber falls behind, the oldest pending
+// change is dropped so notification never blocks.
+func (m *Manager) Subscribe() (<-chan *Config, func()) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.subscribers == nil {
+		m.subscribers = make(map[int]chan *Config)
+	}
+	id := m.nextSubscriberID
+	m.nextSubscriberID++
+
+	ch := make(chan *Config, subscriberBufferSize)
+	m.subscribers[id] = ch
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			m.mutex.Lock()
+			defer m.mutex.Unlock()
+			delete(m.subscribers, id)
+			close(ch)
+		})
 	}
+	return ch, unsubscribe
 }
 
-// notifyWatchers notifies all watchers of configuration changes
-func (m *Manager) notifyWatchers(oldConfig, newConfig *Config) {
+// publish delivers a new configuration to every subscriber, dropping the
+// oldest pending value when a subscriber's buffer is full
+func (m *Manager) publish(newConfig *Config) {
+	for _, ch := range m.subscribers {
+		for delivered := false; !delivered; {
+			select {
+			case ch <- newConfig:
+				delivered = true
+			default:
+				select {
+				case <-ch:
+				default:
+				}
+			}
+		}
+	}
+}
+
+// notifyWatchers notifies all watchers of configuration changes. In
+// synchronous mode it returns the errors of failing watchers as a *WatcherError.
+func (m *Manager) notifyWatchers(oldConfig, newConfig *Config) error {
+	m.publish(newConfig)
+
+	if !m.syncNotify {
+		for _, watcher := range m.watchers {
+			go func(w ConfigWatcher) {
+				w.OnConfigChanged(oldConfig, newConfig)
+			}(watcher)
+		}
+		return nil
+	}
+
+	var errs []error
 	for _, watcher := range m.watchers {
-		go func(w ConfigWatcher) {
-			w.OnConfigChanged(oldConfig, newConfig)
-		}(watcher)
+		if ew, ok := watcher.(*errorWatcher); ok {
+			if err := ew.watcher.OnConfigChanged(oldConfig, newConfig); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		watcher.OnConfigChanged(oldConfig, newConfig)
+	}
+	if len(errs) > 0 {
+		return &WatcherError{Errors: errs}
 	}
+	return nil
+}
+
+// errorWatcher adapts a ConfigWatcherWithError to ConfigWatcher, discarding
+// its error when notified asynchronously
+type errorWatcher struct {
+	watcher ConfigWatcherWithError
+}
+
+// OnConfigChanged forwards the change and discards the watcher's error
+func (w *errorWatcher) OnConfigChanged(oldConfig, newConfig *Config) {
+	_ = w.watcher.OnConfigChanged(oldConfig, newConfig)
+}
+
+// priorityWatcher wraps a watcher added with a notification priority
+type priorityWatcher struct {
+	watcher  ConfigWatcher
+	priority int
+}
+
+// OnConfigChanged forwards the change to the wrapped watcher
+func (w *priorityWatcher) OnConfigChanged(oldConfig, newConfig *Config) {
+	w.watcher.OnConfigChanged(oldConfig, newConfig)
 }
 
-// Reload reloads the configuration from the current source
+// watcherPriority returns the notification priority of a registered watcher
+func watcherPriority(watcher ConfigWatcher) int {
+	if pw, ok := watcher.(*priorityWatcher); ok {
+		return pw.priority
+	}
+	return 0
+}
+
+// fieldWatcher wraps a watcher so it only fires for changes to specific fields
+type fieldWatcher struct {
+	fields  []string
+	watcher ConfigWatcher
+}
+
+// OnConfigChanged forwards the change if any watched field differs
+func (w *fieldWatcher) OnConfigChanged(oldConfig, newConfig *Config) {
+	for _, change := range Diff(oldConfig, newConfig) {
+		for _, field := range w.fields {
+			if matchesField(change.Field, field) {
+				w.watcher.OnConfigChanged(oldConfig, newConfig)
+				return
+			}
+		}
+	}
+}
+
+// Reload reloads the configuration from the current source. Like Load, a
+// failed reload keeps the previous configuration in place, and overrides set
+// with SetOverride are reapplied before the result is validated and watchers
+// are notified. In synchronous notification mode a *WatcherError reports
+// that the new configuration was installed but not every watcher applied it.
 func (m *Manager) Reload() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
 	// Determine the current strategy based on environment
 	strategy := EnvironmentStrategy
-	if m.config != nil && m.config.App.Environment == "production" {
+	if config := m.config.Load(); config != nil && config.App.Environment == "production" {
 		strategy = FileStrategy
 	}
+	return m.loadLocked(strategy, true)
+}
 
-	return m.Load(strategy)
+// Warnings returns the advisory findings for the current configuration:
+// warnings from the load that produced it, such as unset environment variable
+// references, followed by validator warnings such as a nonstandard SMTP port.
+// They are replaced on the next successful load or update.
+func (m *Manager) Warnings() []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	warnings := append([]string(nil), m.loadWarnings...)
+	return append(warnings, m.validationWarnings...)
 }
 
-// IsLoaded returns true if configuration has been loaded
-func (m *Manager) IsLoaded() bool {
+// LastLoadTime returns when configuration was last successfully loaded,
+// including reloads, or the zero time if it never was
+func (m *Manager) LastLoadTime() time.Time {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
-	return m.config != nil
+	return m.lastLoadTime
 }
 
-// ValidateCurrent validates the current configuration
+// LastReloadTime returns when configuration was last successfully reloaded,
+// or the zero time if it never was
+func (m *Manager) LastReloadTime() time.Time {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.lastReloadTime
+}
+
+// maxPreviousJWTSecrets bounds JWT.PreviousSecrets across rotations with
+// UpdateJWTSecret; older secrets are dropped
+const maxPreviousJWTSecrets = 3
+
+// UpdateJWTSecret rotates the JWT secret without a full reload. The current
+// secret is moved to the front of JWT.PreviousSecrets so existing tokens can
+// still be verified, keeping at most the 3 most recent previous secrets, and
+// watchers are notified of the JWT-only change.
+func (m *Manager) UpdateJWTSecret(newSecret string) error {
+	return m.applyUpdate(func(config *Config) error {
+		if newSecret == config.JWT.Secret {
+			return fmt.Errorf("new JWT secret must differ from the current secret")
+		}
+
+		previous := make([]string, 0, len(config.JWT.PreviousSecrets)+1)
+		previous = append(previous, config.JWT.Secret)
+		previous = append(previous, config.JWT.PreviousSecrets...)
+		if len(previous) > maxPreviousJWTSecrets {
+			previous = previous[:maxPreviousJWTSecrets]
+		}
+
+		config.JWT.PreviousSecrets = previous
+		config.JWT.Secret = newSecret
+		return nil
+	}, "jwt.secret", "jwt.previous_secrets")
+}
+
+// SetLogLevel changes the log level at runtime without a full reload. The
+// level is validated like any other configuration and watchers are notified
+// of the log-only change so logging can be reconfigured.
+func (m *Manager) SetLogLevel(level string) error {
+	return m.applyUpdate(func(config *Config) error {
+		config.Log.Level = level
+		return nil
+	}, "log.level")
+}
+
+// applyUpdate applies an in-place change to a copy of the current
+// configuration, validates it and installs it atomically, notifying watchers.
+// The changed fields are reported by FieldSources as SourceOverride until the
+// next load. On any error the current configuration is left unchanged.
+func (m *Manager) applyUpdate(update func(config *Config) error, fields ...string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	current := m.config.Load()
+	if current == nil {
+		return fmt.Errorf("no configuration loaded")
+	}
+	return m.updateLocked(current, update, fields...)
+}
+
+// updateLocked is applyUpdate for callers that already hold the write lock
+// and have checked that a configuration is loaded
+func (m *Manager) updateLocked(current *Config, update func(config *Config) error, fields ...string) error {
+	config := cloneConfig(current)
+	if err := update(&config); err != nil {
+		return err
+	}
+	normalize(&config)
+	sources := make(map[string]string, len(m.sources))
+	for field, source := range m.sources {
+		sources[field] = source
+	}
+	for _, field := range fields {
+		sources[field] = SourceOverride
+	}
+	warnings, err := m.validator.validateWithWarnings(&config, sources)
+	if err != nil {
+		return fmt.Errorf("configuration validation failed: %w", err)
+	}
+	if err := m.checkImmutable(current, &config); err != nil {
+		return err
+	}
+
+	m.config.Store(&config)
+	m.recordHistory(current, &config)
+	m.validationWarnings = warnings
+	m.sources = sources
+	return m.notifyWatchers(current, &config)
+}
+
+// IsLoaded returns true if configuration has been loaded
+func (m *Manager) IsLoaded() bool {
+	return m.config.Load() != nil
+}
+
+// ValidateCurrent validates the current configuration and, if enabled with
+// SetValidateConnectivity, checks that the database and Redis are reachable
 func (m *Manager) ValidateCurrent() error {
+	config := m.config.Load()
+
+	if config == nil {
+		return fmt.Errorf("no configuration loaded")
+	}
+
+	if err := m.validator.Validate(config); err != nil {
+		return err
+	}
+
 	m.mutex.RLock()
-	config := m.config
+	checkConnectivity := m.validateConnectivity
 	m.mutex.RUnlock()
+	if checkConnectivity {
+		if err := m.checkCriticalDependencies(context.Background()); err != nil {
+			return fmt.Errorf("connectivity check failed: %w", err)
+		}
+	}
+	return nil
+}
 
-	if config == nil {
+// IsValid reports whether a configuration is loaded and passes validation,
+// for callers such as health checks that only need a yes or no
+func (m *Manager) IsValid() bool {
+	return m.ValidateCurrent() == nil
+}
+
+// ValidateBindable checks that the configured server address can actually be
+// bound, catching port conflicts before the HTTP server starts
+func (m *Manager) ValidateBindable() error {
+	if !m.IsLoaded() {
 		return fmt.Errorf("no configuration loaded")
 	}
 
-	return m.validator.Validate(config)
+	config := m.GetServerConfig()
+	return m.validator.ValidatePortAvailable(config.Host, config.Port)
 }
 
-// GetDatabaseDSN returns the database connection string (legacy compatibility)
+// GetDatabaseDSN returns the database connection string (legacy compatibility),
+// or an empty string if no configuration is loaded
 func (m *Manager) GetDatabaseDSN() string {
-	config := m.GetDatabaseConfig()
-	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode)
+	current := m.config.Load()
+	if current == nil {
+		return ""
+	}
+
+	config := current.Database
+	return buildDSN(config.DBType, DSNParams{
+		Host: config.Host, Port: config.Port, User: config.User,
+		Password: config.Password, DBName: config.DBName, SSLMode: config.SSLMode,
+	})
 }
 
-// GetWriteDatabaseDSN returns the write database connection string
+// GetWriteDatabaseDSN returns the write database connection string, or an
+// empty string if no configuration is loaded. With a read/write
+// configuration it is built from the write fields, each empty one falling
+// back to its legacy counterpart; otherwise it is the legacy DSN.
 func (m *Manager) GetWriteDatabaseDSN() string {
-	config := m.GetDatabaseConfig()
-
-	// If read/write configuration is set, use it
-	if config.DatabaseConfigType == "read_write" && config.DBWriteHost != "" {
-		return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-			config.DBWriteHost, config.DBWritePort, config.DBWriteUser, config.DBWritePassword,
-			config.DBWriteName, config.SSLMode)
+	current := m.config.Load()
+	if current == nil {
+		return ""
 	}
 
-	// Fallback to legacy configuration
-	return m.GetDatabaseDSN()
+	config := current.Database
+	if resolveDatabaseConfigType(config) != "read_write" {
+		return databaseDSN(config, DSNParams{})
+	}
+	return databaseDSN(config, DSNParams{
+		Host: config.DBWriteHost, Port: config.DBWritePort, User: config.DBWriteUser,
+		Password: config.DBWritePassword, DBName: config.DBWriteName,
+	})
 }
 
-// GetReadDatabaseDSN returns the read database connection string
+// GetReadDatabaseDSN returns the read database connection string, or an
+// empty string if no configuration is loaded. With a read/write
+// configuration it is built from the read fields, each empty one falling
+// back to its legacy counterpart; otherwise it is the legacy DSN.
 func (m *Manager) GetReadDatabaseDSN() string {
-	config := m.GetDatabaseConfig()
+	current := m.config.Load()
+	if current == nil {
+		return ""
+	}
 
-	// If read/write configuration is set, use it
-	if config.DatabaseConfigType == "read_write" && config.DBReadHost != "" {
-		return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-			config.DBReadHost, config.DBReadPort, config.DBReadUser, config.DBReadPassword,
-			config.DBReadName, config.SSLMode)
+	config := current.Database
+	if resolveDatabaseConfigType(config) != "read_write" {
+		return databaseDSN(config, DSNParams{})
 	}
+	return databaseDSN(config, DSNParams{
+		Host: config.DBReadHost, Port: config.DBReadPort, User: config.DBReadUser,
+		Password: config.DBReadPassword, DBName: config.DBReadName,
+	})
+}
 
-	// Fallback to legacy configuration
-	return m.GetDatabaseDSN()
+// databaseDSN builds a DSN for config from the connection fields of params,
+// using the legacy field of config for each one that is empty
+func databaseDSN(config DatabaseConfig, params DSNParams) string {
+	fallback := func(value, legacy string) string {
+		if value == "" {
+			return legacy
+		}
+		return value
+	}
+	return buildDSN(config.DBType, DSNParams{
+		Host:     fallback(params.Host, config.Host),
+		Port:     fallback(params.Port, config.Port),
+		User:     fallback(params.User, config.User),
+		Password: fallback(params.Password, config.Password),
+		DBName:   fallback(params.DBName, config.DBName),
+		SSLMode:  config.SSLMode,
+	})
 }
 
-// IsReadWriteDatabase returns true if read/write database configuration is enabled
-func (m *Manager) IsReadWriteDatabase() bool {
+// GetReadReplicaDSNs returns a connection string for each configured read
+// replica, using the read database credentials and name with the same
+// legacy fallback as GetReadDatabaseDSN. It is empty unless read/write
+// configuration is in use.
+func (m *Manager) GetReadReplicaDSNs() []string {
 	config := m.GetDatabaseConfig()
-	return config.DatabaseConfigType == "read_write" &&
-		config.DBWriteHost != "" && config.DBReadHost != ""
+	if resolveDatabaseConfigType(config) != "read_write" {
+		return nil
+	}
+
+	dsns := make([]string, 0, len(config.ReadReplicas))
+	for _, replica := range config.ReadReplicas {
+		host, port, err := net.SplitHostPort(replica)
+		if err != nil {
+			continue
+		}
+		dsns = append(dsns, databaseDSN(config, DSNParams{
+			Host: host, Port: port, User: config.DBReadUser,
+			Password: config.DBReadPassword, DBName: config.DBReadName,
+		}))
+	}
+	return dsns
 }
 
-// GetDatabaseConfigType returns the database configuration type
+// IsReadWriteDatabase returns true if read/write database configuration is
+// in use: database.config_type is "read_write", or it is "auto_detect" (or
+// empty) and both the write and read hosts are set
+func (m *Manager) IsReadWriteDatabase() bool {
+	return m.GetResolvedDatabaseConfigType() == "read_write"
+}
+
+// Recommended database connection pool sizes per environment
+var recommendedMaxConns = map[string]int{
+	"test":        2,
+	"development": 5,
+	"staging":     20,
+	"production":  50,
+}
+
+// Bounds applied to RecommendedMaxConns
+const (
+	minRecommendedMaxConns = 1
+	maxRecommendedMaxConns = 100
+)
+
+// RecommendedMaxConns suggests a connection pool size for each database pool
+// based on App.Environment. With a read/write split the load is shared by two
+// pools, so each gets half. The result is clamped to between 1 and 100.
+func (m *Manager) RecommendedMaxConns() int {
+	conns, ok := recommendedMaxConns[m.GetAppConfig().Environment]
+	if !ok {
+		conns = recommendedMaxConns["development"]
+	}
+	if m.IsReadWriteDatabase() {
+		conns /= 2
+	}
+
+	if conns < minRecommendedMaxConns {
+		return minRecommendedMaxConns
+	}
+	if conns > maxRecommendedMaxConns {
+		return maxRecommendedMaxConns
+	}
+	return conns
+}
+
+// GetDatabaseConfigType returns the database configuration type as
+// configured, which may be "auto_detect" or empty
 func (m *Manager) GetDatabaseConfigType() string {
 	config := m.GetDatabaseConfig()
 	return config.DatabaseConfigType
 }
 
-// GetRedisAddr returns the Redis address
+// GetResolvedDatabaseConfigType returns the database configuration type in
+// use, "read_write" or "legacy", resolving "auto_detect" (or an empty type)
+// from the hosts that are set. It returns an empty string if no
+// configuration is loaded.
+func (m *Manager) GetResolvedDatabaseConfigType() string {
+	current := m.config.Load()
+	if current == nil {
+		return ""
+	}
+	return resolveDatabaseConfigType(current.Database)
+}
+
+// resolveDatabaseConfigType returns the configuration type of config,
+// detecting read/write configuration when both of its hosts are set
+func resolveDatabaseConfigType(config DatabaseConfig) string {
+	switch {
+	case strings.EqualFold(config.DatabaseConfigType, "read_write"):
+		return "read_write"
+	case strings.EqualFold(config.DatabaseConfigType, "legacy"):
+		return "legacy"
+	case config.DBWriteHost != "" && config.DBReadHost != "":
+		return "read_write"
+	default:
+		return "legacy"
+	}
+}
+
+// GetRedisAddr returns the Redis address, or an empty string if no
+// configuration is loaded. A configured URL takes precedence over the
+// host and port fields.
 func (m *Manager) GetRedisAddr() string {
-	config := m.GetRedisConfig()
-	return fmt.Sprintf("%s:%s", config.Host, config.Port)
+	current := m.config.Load()
+	if current == nil {
+		return ""
+	}
+	redis := resolveRedisConfig(current.Redis)
+	return net.JoinHostPort(redis.Host, redis.Port)
+}
+
+// GetResolvedRedisConfig returns the Redis configuration with the host,
+// port, password and database taken from the URL when one is configured
+func (m *Manager) GetResolvedRedisConfig() RedisConfig {
+	current := m.config.Load()
+	if current == nil {
+		return RedisConfig{}
+	}
+	return resolveRedisConfig(current.Redis)
+}
+
+// defaultRedisPort is used when a Redis URL names a host but no port
+const defaultRedisPort = "6379"
+
+// resolveRedisConfig overlays the parts of a redis:// URL onto the
+// individual connection fields. Parts missing from the URL keep their
+// field values, except the port, which defaults to 6379 as in Redis
+// clients. An unparsable URL leaves the config unchanged; the validator
+// reports it.
+func resolveRedisConfig(config RedisConfig) RedisConfig {
+	if config.URL == "" {
+		return config
+	}
+	u, err := url.Parse(config.URL)
+	if err != nil || (u.Scheme != "redis" && u.Scheme != "rediss") {
+		return config
+	}
+	db, ok, err := redisURLDB(config.URL)
+	if err != nil {
+		return config
+	}
+
+	if host := u.Hostname(); host != "" {
+		config.Host = host
+		config.Port = defaultRedisPort
+		if port := u.Port(); port != "" {
+			config.Port = port
+		}
+	}
+	if password, set := u.User.Password(); set {
+		config.Password = password
+	}
+	if ok {
+		config.DB = db
+	}
+	return config
 }
 
-// GetServerAddr returns the server address
+// GetServerAddr returns the server address, or an empty string if no
+// configuration is loaded
 func (m *Manager) GetServerAddr() string {
-	config := m.GetServerConfig()
-	return fmt.Sprintf("%s:%s", config.Host, config.Port)
+	current := m.config.Load()
+	if current == nil {
+		return ""
+	}
+	return net.JoinHostPort(current.Server.Host, current.Server.Port)
 }
 
 // IsDevelopment returns true if the application is in development mode
@@ -271,3 +1028,16 @@ func (m *Manager) IsDebug() bool {
 	config := m.GetAppConfig()
 	return config.Debug
 }
+
+// MetricSafeAppName returns the application name converted to an identifier
+// that is safe to use as a logging or metrics label, e.g. "my_app"
+func (m *Manager) MetricSafeAppName() string {
+	return MetricSafeName(m.GetAppConfig().Name)
+}
+
+// IsMaintenanceMode returns true if maintenance mode is enabled. Watch
+// "app.maintenance_mode" with AddWatcherFor to react when it is toggled.
+func (m *Manager) IsMaintenanceMode() bool {
+	config := m.GetAppConfig()
+	return config.MaintenanceMode
+}
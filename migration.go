@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// MigrationFunc upgrades raw configuration settings from one schema version to the next
+type MigrationFunc func(settings map[string]interface{}) map[string]interface{}
+
+// RegisterMigration registers a migration that upgrades settings from the given
+// schema version to version from+1. Files without a schema_version are treated
+// as version 1.
+func (l *Loader) RegisterMigration(from int, fn MigrationFunc) {
+	if l.migrations == nil {
+		l.migrations = make(map[int]MigrationFunc)
+	}
+	l.migrations[from] = fn
+}
+
+// migrate applies registered migrations to the settings read from a file
+func (l *Loader) migrate() error {
+	if len(l.migrations) == 0 {
+		return nil
+	}
+
+	version := 1
+	if l.viper.IsSet("schema_version") {
+		version = l.viper.GetInt("schema_version")
+	}
+
+	fn, ok := l.migrations[version]
+	if !ok {
+		return nil
+	}
+
+	settings := l.viper.AllSettings()
+	for ok {
+		settings = fn(settings)
+		if settings == nil {
+			return fmt.Errorf("migration from schema version %d returned no settings", version)
+		}
+		version++
+		fn, ok = l.migrations[version]
+	}
+	settings["schema_version"] = version
+
+	// Replace the settings rather than merging them, so that keys a
+	// migration renames or removes do not linger next to their replacements
+	migrated := viper.New()
+	if err := migrated.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("failed to apply migrated config: %w", err)
+	}
+	l.viper = migrated
+	return nil
+}
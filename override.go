@@ -0,0 +1,68 @@
+package config
+
+import "fmt"
+
+// SetOverride pins the field at path (e.g. "log.level") to value on top of
+// every subsequent Load, LoadConfig and Reload, whatever the other sources
+// say, until ClearOverride is called. It is intended for emergency
+// operational changes. String values are parsed like environment variables.
+// If a configuration is loaded, the override is applied to it immediately
+// and watchers are notified; an override that would make the current
+// configuration invalid is rejected.
+func (m *Manager) SetOverride(path string, value interface{}) error {
+	if err := setFieldValue(&Config{}, path, value); err != nil {
+		return fmt.Errorf("invalid override: %w", err)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var watcherErr error
+	if current := m.config.Load(); current != nil {
+		err := m.updateLocked(current, func(config *Config) error {
+			return setFieldValue(config, path, value)
+		}, path)
+		if err != nil && !isWatcherError(err) {
+			return err
+		}
+		watcherErr = err
+	}
+
+	if m.overrides == nil {
+		m.overrides = make(map[string]interface{})
+	}
+	m.overrides[path] = value
+	return watcherErr
+}
+
+// ClearOverride removes the override for path. The field keeps its pinned
+// value until the next load or reload, which takes it from the regular sources.
+func (m *Manager) ClearOverride(path string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.overrides, path)
+}
+
+// Overrides returns the active overrides keyed by dotted field path
+func (m *Manager) Overrides() map[string]interface{} {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	overrides := make(map[string]interface{}, len(m.overrides))
+	for path, value := range m.overrides {
+		overrides[path] = value
+	}
+	return overrides
+}
+
+// applyOverrides applies the active overrides to a freshly loaded
+// configuration and records them in sources. The caller must hold the lock.
+func (m *Manager) applyOverrides(config *Config, sources map[string]string) error {
+	for path, value := range m.overrides {
+		if err := setFieldValue(config, path, value); err != nil {
+			return fmt.Errorf("failed to apply override: %w", err)
+		}
+		sources[path] = SourceOverride
+	}
+	return nil
+}
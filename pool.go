@@ -0,0 +1,61 @@
+package config
+
+import (
+	"database/sql"
+	"time"
+)
+
+// SQLPoolConfig holds connection pool settings for a *sql.DB
+type SQLPoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// Apply configures db's connection pool
+func (p SQLPoolConfig) Apply(db *sql.DB) {
+	db.SetMaxOpenConns(p.MaxOpenConns)
+	db.SetMaxIdleConns(p.MaxIdleConns)
+	db.SetConnMaxLifetime(p.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(p.ConnMaxIdleTime)
+}
+
+// SQLPoolConfig returns the database/sql pool settings derived from the
+// database configuration. Unset (zero) values fall back to the defaults of
+// their environment variables, and MaxIdleConns never exceeds MaxOpenConns.
+// As with database/sql, a negative MaxIdleConns disables idle connections
+// and is returned as 0.
+func (m *Manager) SQLPoolConfig() SQLPoolConfig {
+	config := m.GetDatabaseConfig()
+
+	pool := SQLPoolConfig{
+		MaxOpenConns:    config.MaxConns,
+		MaxIdleConns:    config.MaxIdleConns,
+		ConnMaxLifetime: config.ConnMaxLifetime,
+		ConnMaxIdleTime: config.ConnMaxIdleTime,
+	}
+
+	defaults := &Config{}
+	for _, field := range []string{"database.max_conns", "database.max_idle_conns", "database.conn_max_lifetime", "database.conn_max_idle_time"} {
+		_ = setFieldFromString(defaults, field, bindingDefault(field))
+	}
+	if pool.MaxOpenConns <= 0 {
+		pool.MaxOpenConns = defaults.Database.MaxConns
+	}
+	if pool.MaxIdleConns < 0 {
+		pool.MaxIdleConns = 0
+	} else if pool.MaxIdleConns == 0 {
+		pool.MaxIdleConns = defaults.Database.MaxIdleConns
+	}
+	if pool.ConnMaxLifetime <= 0 {
+		pool.ConnMaxLifetime = defaults.Database.ConnMaxLifetime
+	}
+	if pool.ConnMaxIdleTime <= 0 {
+		pool.ConnMaxIdleTime = defaults.Database.ConnMaxIdleTime
+	}
+	if pool.MaxIdleConns > pool.MaxOpenConns {
+		pool.MaxIdleConns = pool.MaxOpenConns
+	}
+	return pool
+}
@@ -0,0 +1,330 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Field sources reported by FieldSources
+const (
+	SourceDefault  = "default"
+	SourceFile     = "file"
+	SourceEnv      = "env"
+	SourceMemory   = "memory"
+	SourceOverride = "override"
+)
+
+// FieldSources returns the source that produced each field of the most recent
+// load, keyed by dotted field path, e.g. "server.port" -> "env"
+func (l *Loader) FieldSources() map[string]string {
+	sources := make(map[string]string, len(l.sources))
+	for field, source := range l.sources {
+		sources[field] = source
+	}
+	return sources
+}
+
+// SourceKind identifies a configuration source layered by HybridStrategy.
+// Its values match the sources reported by FieldSources.
+type SourceKind string
+
+// Sources that can be ordered with Loader.SetSourcePrecedence
+const (
+	DefaultsSource SourceKind = SourceDefault
+	FileSource     SourceKind = SourceFile
+	EnvSource      SourceKind = SourceEnv
+)
+
+// defaultSourcePrecedence is the merge order used by HybridStrategy unless
+// SetSourcePrecedence is called
+var defaultSourcePrecedence = []SourceKind{DefaultsSource, FileSource, EnvSource}
+
+// SetSourcePrecedence sets the order in which HybridStrategy merges sources.
+// Each source overrides the fields it sets in the sources before it, so the
+// last one takes precedence; sources left out are not read at all. For
+// example, []SourceKind{DefaultsSource, EnvSource, FileSource} lets the file
+// override the environment. When a precedence is set, the file layer holds
+// only values from the file itself, without environment overrides of its
+// keys. A nil slice restores the default order of defaults, file, then env.
+func (l *Loader) SetSourcePrecedence(sources []SourceKind) {
+	if sources == nil {
+		l.precedence = nil
+		return
+	}
+	l.precedence = append(make([]SourceKind, 0, len(sources)), sources...)
+}
+
+// sourcePrecedence returns the configured merge order, checking that it only
+// holds known sources, each at most once
+func (l *Loader) sourcePrecedence() ([]SourceKind, error) {
+	if l.precedence == nil {
+		return defaultSourcePrecedence, nil
+	}
+
+	seen := make(map[SourceKind]bool, len(l.precedence))
+	for _, source := range l.precedence {
+		switch source {
+		case DefaultsSource, FileSource, EnvSource:
+		default:
+			return nil, fmt.Errorf("invalid source precedence: unknown source %q", source)
+		}
+		if seen[source] {
+			return nil, fmt.Errorf("invalid source precedence: %q listed more than once", source)
+		}
+		seen[source] = true
+	}
+	return l.precedence, nil
+}
+
+// loadHybrid builds a configuration by layering defaults, the file named by
+// --config or CONFIG_PATH (if any), and explicitly-set environment variables in the
+// order set by SetSourcePrecedence, recording which layer produced each field
+func (l *Loader) loadHybrid() (*Config, error) {
+	precedence, err := l.sourcePrecedence()
+	if err != nil {
+		return nil, err
+	}
+
+	l.envErrors = nil
+	l.warnings = nil
+	l.loadedFiles = nil
+	l.resetEnvRead()
+
+	config := &Config{}
+	sources := defaultSources()
+	values := make(layerValues)
+	for _, source := range precedence {
+		switch source {
+		case DefaultsSource:
+			err = l.applyDefaultsLayer(config, sources)
+		case FileSource:
+			err = l.applyFileLayer(config, sources, values)
+		case EnvSource:
+			l.applyEnvLayer(config, sources, values)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	l.sources = sources
+	l.conflicts = values.conflicts(sources)
+	if err := l.strictEnvError(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// applyDefaultsLayer sets every bound field to its default
+func (l *Loader) applyDefaultsLayer(config *Config, sources map[string]string) error {
+	for _, binding := range envBindings {
+		if err := setFieldFromString(config, binding.Field, binding.Default); err != nil {
+			return err
+		}
+		sources[binding.Field] = SourceDefault
+	}
+	return nil
+}
+
+// applyFileLayer sets the fields specified by the file named by --config or
+// CONFIG_PATH, if any, recording the values the file itself holds in values.
+// A file that cannot be loaded fails the load, as with FileStrategy.
+func (l *Loader) applyFileLayer(config *Config, sources map[string]string, values layerValues) error {
+	configPath := l.explicitConfigPath()
+	if configPath == "" {
+		return nil
+	}
+
+	l.fileOnly = true
+	fileConfig, err := l.LoadFromFile(configPath)
+	l.fileOnly = false
+	if err != nil {
+		return err
+	}
+
+	for field, source := range l.sources {
+		if source == SourceDefault {
+			continue
+		}
+		values.record(fileConfig, field, SourceFile)
+
+		// With the default precedence, environment overrides of file keys
+		// apply within the file layer, as they do for FileStrategy
+		if l.precedence == nil && l.overrideFileKey(fileConfig, field) {
+			source = SourceEnv
+		}
+
+		src, _ := lookupField(fileConfig, field)
+		dst, _ := lookupField(config, field)
+		dst.Set(src)
+		sources[field] = source
+	}
+	return nil
+}
+
+// overrideFileKey sets field in config from the environment variable named
+// by the key replacer, if set, and reports whether it did. Unparsable values
+// are left to the environment layer to report.
+func (l *Loader) overrideFileKey(config *Config, field string) bool {
+	value := l.getenv(l.envKeyName(field))
+	return value != "" && setFieldFromString(config, field, value) == nil
+}
+
+// applyEnvLayer sets the fields whose environment variables are set,
+// recording their values in values
+func (l *Loader) applyEnvLayer(config *Config, sources map[string]string, values layerValues) {
+	for _, binding := range envBindings {
+		value, err := l.bindingEnv(binding.Name)
+		if err != nil {
+			l.envErrors = append(l.envErrors, err)
+		}
+		if value == "" {
+			if err == nil && l.setExplicitlyEmpty(config, binding) {
+				sources[binding.Field] = SourceEnv
+				values.record(config, binding.Field, SourceEnv)
+			}
+			continue
+		}
+		if err := setFieldFromString(config, binding.Field, value); err != nil {
+			l.envErrors = append(l.envErrors, fmt.Errorf("%s: %w", binding.Name, err))
+			continue
+		}
+		sources[binding.Field] = SourceEnv
+		values.record(config, binding.Field, SourceEnv)
+	}
+
+	if l.loadOperationTimeouts(config) {
+		sources["server.timeouts"] = SourceEnv
+	}
+}
+
+// recordFileSources records the source of each field after a file load.
+// Fields overridden through viper's automatic environment binding are
+// attributed to the environment.
+func (l *Loader) recordFileSources() {
+	sources := defaultSources()
+	for field := range sources {
+		if !l.viper.InConfig(field) {
+			continue
+		}
+		sources[field] = SourceFile
+		if !l.fileOnly && l.getenv(l.envKeyName(field)) != "" {
+			sources[field] = SourceEnv
+		}
+	}
+	l.sources = sources
+}
+
+// defaultSources returns every leaf field path mapped to SourceDefault
+func defaultSources() map[string]string {
+	sources := make(map[string]string)
+	for _, field := range leafFields("", reflect.TypeOf(Config{})) {
+		sources[field] = SourceDefault
+	}
+	return sources
+}
+
+// memorySources returns every leaf field path mapped to SourceMemory, for
+// configurations installed with Manager.LoadConfig
+func memorySources() map[string]string {
+	sources := defaultSources()
+	for field := range sources {
+		sources[field] = SourceMemory
+	}
+	return sources
+}
+
+// leafFields returns the dotted paths of every non-struct field of t
+func leafFields(prefix string, t reflect.Type) []string {
+	fields := make([]string, 0)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		path := fieldPath(prefix, field)
+		if field.Type.Kind() == reflect.Struct {
+			fields = append(fields, leafFields(path, field.Type)...)
+			continue
+		}
+		fields = append(fields, path)
+	}
+	return fields
+}
+
+// Conflict describes a field that more than one source set to different
+// values during a hybrid load
+type Conflict struct {
+	Field  string                 `json:"field"`  // e.g., "server.port"
+	Values map[string]interface{} `json:"values"` // value per source, e.g., {"file": "8080", "env": "9090"}; secrets redacted
+	Winner string                 `json:"winner"` // source whose value was used, e.g., "env"
+}
+
+// Conflicts returns the fields that the file and the environment set to
+// different values in the last HybridStrategy load, sorted by field, with
+// the source whose value won under the source precedence. Defaults never
+// conflict. Other strategies report no conflicts.
+func (l *Loader) Conflicts() []Conflict {
+	return append([]Conflict(nil), l.conflicts...)
+}
+
+// Conflicts returns the source conflicts of the load that produced the
+// current configuration, as reported by Loader.Conflicts
+func (m *Manager) Conflicts() []Conflict {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return append([]Conflict(nil), m.conflicts...)
+}
+
+// layerValues records the value each hybrid layer set, keyed by dotted field
+// path and then by source
+type layerValues map[string]map[string]interface{}
+
+// record stores the value of field in config as set by source
+func (lv layerValues) record(config *Config, field, source string) {
+	value, ok := lookupField(config, field)
+	if !ok {
+		return
+	}
+	if lv[field] == nil {
+		lv[field] = make(map[string]interface{})
+	}
+	lv[field][source] = value.Interface()
+}
+
+// conflicts returns the fields recorded with differing values, naming the
+// source that produced each field's final value
+func (lv layerValues) conflicts(sources map[string]string) []Conflict {
+	conflicts := make([]Conflict, 0)
+	for field, bySource := range lv {
+		if !valuesDiffer(bySource) {
+			continue
+		}
+
+		values := make(map[string]interface{}, len(bySource))
+		for source, value := range bySource {
+			if isSecretField(field) {
+				value = redactedValue
+			}
+			values[source] = value
+		}
+		conflicts = append(conflicts, Conflict{Field: field, Values: values, Winner: sources[field]})
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Field < conflicts[j].Field })
+	return conflicts
+}
+
+// valuesDiffer reports whether the recorded values are not all equal
+func valuesDiffer(values map[string]interface{}) bool {
+	var first interface{}
+	seen := false
+	for _, value := range values {
+		if seen && !reflect.DeepEqual(first, value) {
+			return true
+		}
+		first, seen = value, true
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// byteSizeUnits maps the accepted byte-size suffixes, in lower case, to their
+// multipliers: SI units are powers of 1000, IEC units powers of 1024
+var byteSizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+// ApplyRuntimeConfig applies the runtime section of the current configuration:
+// GOMAXPROCS is set to runtime.max_procs and the soft memory limit to
+// runtime.memory_limit. Zero or empty values leave the setting unchanged.
+// It is not applied automatically on load or reload.
+func (m *Manager) ApplyRuntimeConfig() error {
+	config := m.config.Load()
+	if config == nil {
+		return fmt.Errorf("no configuration loaded")
+	}
+
+	var limit int64
+	if config.Runtime.MemoryLimit != "" {
+		var err error
+		if limit, err = parseByteSize(config.Runtime.MemoryLimit); err != nil {
+			return fmt.Errorf("invalid runtime memory limit: %w", err)
+		}
+		if limit <= 0 {
+			return fmt.Errorf("runtime memory limit must be positive, got %q", config.Runtime.MemoryLimit)
+		}
+	}
+	if config.Runtime.MaxProcs < 0 {
+		return fmt.Errorf("runtime max procs cannot be negative, got %d", config.Runtime.MaxProcs)
+	}
+
+	if config.Runtime.MaxProcs > 0 {
+		runtime.GOMAXPROCS(config.Runtime.MaxProcs)
+	}
+	if limit > 0 {
+		debug.SetMemoryLimit(limit)
+	}
+	return nil
+}
+
+// parseByteSize parses a size such as "512MiB", "2GB" or "1048576" into bytes.
+// Units are case-insensitive; a bare number is a count of bytes.
+func parseByteSize(s string) (int64, error) {
+	trimmed := strings.TrimSpace(s)
+	split := strings.IndexFunc(trimmed, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if split < 0 {
+		split = len(trimmed)
+	}
+
+	number, unit := trimmed[:split], strings.ToLower(strings.TrimSpace(trimmed[split:]))
+	multiplier, ok := byteSizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size %q: unknown unit %q", s, unit)
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+
+	bytes := value * multiplier
+	if bytes > math.MaxInt64 {
+		return 0, fmt.Errorf("byte size %q is too large", s)
+	}
+	return int64(bytes), nil
+}
@@ -0,0 +1,395 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidateAgainstSchema validates the current configuration against the JSON
+// Schema in schemaPath, complementing the built-in validator. The
+// configuration is checked in its JSON form, keyed by the same names as
+// config files (e.g. "jwt": {"secret": ...}), with durations as strings such
+// as "30s". Every violation is reported in a single *ValidationError.
+//
+// The supported keywords are type, enum, const, properties, required,
+// additionalProperties, items, minItems, maxItems, minLength, maxLength,
+// pattern, minimum, maximum, exclusiveMinimum and exclusiveMaximum.
+// Annotations such as title and description are ignored. Any other keyword,
+// including $ref, allOf and format, makes the schema invalid rather than
+// being skipped silently.
+func (m *Manager) ValidateAgainstSchema(schemaPath string) error {
+	config := m.GetConfig()
+	if config == nil {
+		return fmt.Errorf("no configuration loaded")
+	}
+
+	data, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return fmt.Errorf("failed to read schema: %w", err)
+	}
+	var schema interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return fmt.Errorf("invalid schema %s: %w", schemaPath, err)
+	}
+
+	document, err := schemaDocument(config)
+	if err != nil {
+		return err
+	}
+
+	if err := checkSchemaKeywords(schema, ""); err != nil {
+		return fmt.Errorf("invalid schema %s: %w", schemaPath, err)
+	}
+
+	var violations []FieldError
+	if err := checkSchema(schema, document, "", &violations); err != nil {
+		return fmt.Errorf("invalid schema %s: %w", schemaPath, err)
+	}
+	if len(violations) > 0 {
+		return newValidationError(violations)
+	}
+	return nil
+}
+
+// schemaDocument converts config into its generic JSON form
+func schemaDocument(config *Config) (interface{}, error) {
+	data, err := json.Marshal(schemaValue(reflect.ValueOf(*config)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	return document, nil
+}
+
+// schemaValue converts v into JSON-encodable values keyed by mapstructure
+// name, rendering durations as strings
+func schemaValue(v reflect.Value) interface{} {
+	switch {
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		return time.Duration(v.Int()).String()
+	case v.Type() == reflect.TypeOf(json.RawMessage{}):
+		return v.Interface()
+	case v.Kind() == reflect.Struct:
+		m := make(map[string]interface{}, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.IsExported() {
+				m[fieldPath("", field)] = schemaValue(v.Field(i))
+			}
+		}
+		return m
+	case v.Kind() == reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		m := make(map[string]interface{}, v.Len())
+		for _, key := range v.MapKeys() {
+			m[fmt.Sprint(key.Interface())] = schemaValue(v.MapIndex(key))
+		}
+		return m
+	case v.Kind() == reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = schemaValue(v.Index(i))
+		}
+		return items
+	default:
+		return v.Interface()
+	}
+}
+
+// schemaKeywords lists the keywords checkSchema enforces
+var schemaKeywords = []string{
+	"type", "enum", "const", "properties", "required", "additionalProperties",
+	"items", "minItems", "maxItems", "minLength", "maxLength", "pattern",
+	"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum",
+}
+
+// schemaAnnotations lists keywords that carry no validation rules
+var schemaAnnotations = []string{
+	"$schema", "$id", "$comment", "title", "description", "default", "examples",
+	"deprecated", "readOnly", "writeOnly",
+}
+
+// checkSchemaKeywords returns an error for the first keyword in schema, or in
+// any of its subschemas, that checkSchema does not enforce. path locates the
+// subschema by the field it applies to.
+func checkSchemaKeywords(schema interface{}, path string) error {
+	rules, ok := schema.(map[string]interface{})
+	if !ok {
+		if _, ok := schema.(bool); !ok {
+			return fmt.Errorf("schema for %s must be an object or a boolean", schemaSubject(path))
+		}
+		return nil
+	}
+
+	keys := make([]string, 0, len(rules))
+	for key := range rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !containsString(schemaKeywords, key) && !containsString(schemaAnnotations, key) {
+			return fmt.Errorf("unsupported keyword %q in schema for %s", key, schemaSubject(path))
+		}
+	}
+
+	if properties, ok := rules["properties"].(map[string]interface{}); ok {
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := checkSchemaKeywords(properties[name], schemaPath(path, name)); err != nil {
+				return err
+			}
+		}
+	}
+	if additional, ok := rules["additionalProperties"]; ok {
+		if err := checkSchemaKeywords(additional, schemaPath(path, "*")); err != nil {
+			return err
+		}
+	}
+	if items, ok := rules["items"]; ok {
+		if err := checkSchemaKeywords(items, path+"[]"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSchema appends the violations of value against schema, a decoded JSON
+// Schema, to violations. It returns an error only for malformed schemas.
+func checkSchema(schema, value interface{}, path string, violations *[]FieldError) error {
+	rules, ok := schema.(map[string]interface{})
+	if !ok {
+		// Boolean schemas: true accepts everything, false nothing
+		if schema == false {
+			addSchemaViolation(violations, path, "is not allowed")
+		}
+		return nil
+	}
+
+	if types, ok := rules["type"]; ok && !schemaTypeMatches(types, value) {
+		addSchemaViolation(violations, path, fmt.Sprintf("must be of type %s", schemaTypeNames(types)))
+		return nil
+	}
+	if options, ok := rules["enum"].([]interface{}); ok && !schemaContains(options, value) {
+		addSchemaViolation(violations, path, fmt.Sprintf("must be one of %s", schemaJSON(options)))
+	}
+	if constant, ok := rules["const"]; ok && !reflect.DeepEqual(constant, value) {
+		addSchemaViolation(violations, path, fmt.Sprintf("must be %s", schemaJSON(constant)))
+	}
+
+	switch v := value.(type) {
+	case string:
+		return checkSchemaString(rules, v, path, violations)
+	case float64:
+		checkSchemaNumber(rules, v, path, violations)
+	case []interface{}:
+		return checkSchemaArray(rules, v, path, violations)
+	case map[string]interface{}:
+		return checkSchemaObject(rules, v, path, violations)
+	}
+	return nil
+}
+
+// checkSchemaString applies the string keywords
+func checkSchemaString(rules map[string]interface{}, value, path string, violations *[]FieldError) error {
+	length := float64(utf8.RuneCountInString(value))
+	if limit, ok := rules["minLength"].(float64); ok && length < limit {
+		addSchemaViolation(violations, path, fmt.Sprintf("must be at least %v characters long", limit))
+	}
+	if limit, ok := rules["maxLength"].(float64); ok && length > limit {
+		addSchemaViolation(violations, path, fmt.Sprintf("must be at most %v characters long", limit))
+	}
+	if pattern, ok := rules["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		if !re.MatchString(value) {
+			addSchemaViolation(violations, path, fmt.Sprintf("must match pattern %q", pattern))
+		}
+	}
+	return nil
+}
+
+// checkSchemaNumber applies the numeric keywords
+func checkSchemaNumber(rules map[string]interface{}, value float64, path string, violations *[]FieldError) {
+	if limit, ok := rules["minimum"].(float64); ok && value < limit {
+		addSchemaViolation(violations, path, fmt.Sprintf("must be at least %v", limit))
+	}
+	if limit, ok := rules["maximum"].(float64); ok && value > limit {
+		addSchemaViolation(violations, path, fmt.Sprintf("must be at most %v", limit))
+	}
+	if limit, ok := rules["exclusiveMinimum"].(float64); ok && value <= limit {
+		addSchemaViolation(violations, path, fmt.Sprintf("must be greater than %v", limit))
+	}
+	if limit, ok := rules["exclusiveMaximum"].(float64); ok && value >= limit {
+		addSchemaViolation(violations, path, fmt.Sprintf("must be less than %v", limit))
+	}
+}
+
+// checkSchemaArray applies the array keywords, checking each item
+func checkSchemaArray(rules map[string]interface{}, value []interface{}, path string, violations *[]FieldError) error {
+	if limit, ok := rules["minItems"].(float64); ok && float64(len(value)) < limit {
+		addSchemaViolation(violations, path, fmt.Sprintf("must have at least %v items", limit))
+	}
+	if limit, ok := rules["maxItems"].(float64); ok && float64(len(value)) > limit {
+		addSchemaViolation(violations, path, fmt.Sprintf("must have at most %v items", limit))
+	}
+	if items, ok := rules["items"]; ok {
+		for i, item := range value {
+			if err := checkSchema(items, item, fmt.Sprintf("%s[%d]", path, i), violations); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkSchemaObject applies the object keywords, checking each property
+func checkSchemaObject(rules map[string]interface{}, value map[string]interface{}, path string, violations *[]FieldError) error {
+	if required, ok := rules["required"].([]interface{}); ok {
+		for _, name := range required {
+			if key, ok := name.(string); ok {
+				if _, present := value[key]; !present {
+					addSchemaViolation(violations, schemaPath(path, key), "is required")
+				}
+			}
+		}
+	}
+
+	properties, _ := rules["properties"].(map[string]interface{})
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		propertySchema, declared := properties[key]
+		if !declared {
+			additional, ok := rules["additionalProperties"]
+			if !ok {
+				continue
+			}
+			propertySchema = additional
+		}
+		if err := checkSchema(propertySchema, value[key], schemaPath(path, key), violations); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schemaTypeMatches reports whether value has one of the JSON types named by
+// types, a string or an array of strings
+func schemaTypeMatches(types, value interface{}) bool {
+	names, ok := types.([]interface{})
+	if !ok {
+		names = []interface{}{types}
+	}
+	for _, name := range names {
+		switch name {
+		case "string":
+			if _, ok := value.(string); ok {
+				return true
+			}
+		case "number":
+			if _, ok := value.(float64); ok {
+				return true
+			}
+		case "integer":
+			if f, ok := value.(float64); ok && f == math.Trunc(f) {
+				return true
+			}
+		case "boolean":
+			if _, ok := value.(bool); ok {
+				return true
+			}
+		case "object":
+			if _, ok := value.(map[string]interface{}); ok {
+				return true
+			}
+		case "array":
+			if _, ok := value.([]interface{}); ok {
+				return true
+			}
+		case "null":
+			if value == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// schemaTypeNames renders a type keyword for messages, e.g. "string or null"
+func schemaTypeNames(types interface{}) string {
+	names, ok := types.([]interface{})
+	if !ok {
+		return fmt.Sprint(types)
+	}
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprint(name)
+	}
+	return strings.Join(parts, " or ")
+}
+
+// schemaContains reports whether options holds value
+func schemaContains(options []interface{}, value interface{}) bool {
+	for _, option := range options {
+		if reflect.DeepEqual(option, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaJSON renders a schema value for messages
+func schemaJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// schemaPath appends key to the dotted path
+func schemaPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// schemaSubject names the field at path in messages, "config" for the root
+func schemaSubject(path string) string {
+	if path == "" {
+		return "config"
+	}
+	return path
+}
+
+// addSchemaViolation records a violation for the field at path
+func addSchemaViolation(violations *[]FieldError, path, problem string) {
+	*violations = append(*violations, FieldError{Field: path, Message: schemaSubject(path) + " " + problem})
+}
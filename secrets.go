@@ -0,0 +1,87 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// secretRefPrefix marks string values that are resolved through the loader's
+// SecretProvider, e.g. "secret://db/password"
+const secretRefPrefix = "secret://"
+
+// SecretProvider resolves secret references, such as entries in Vault or a
+// cloud secret manager, so that secrets need not be stored in files or the
+// environment
+type SecretProvider interface {
+	// GetSecret returns the secret named by ref, the part of a reference
+	// after "secret://", e.g. "db/password"
+	GetSecret(ctx context.Context, ref string) (string, error)
+}
+
+// SetSecretProvider sets the provider used by Load to resolve string values
+// of the form "secret://<ref>", whether they come from a file or the
+// environment. References are resolved before templates and validation. A
+// reference without a provider fails the load.
+func (l *Loader) SetSecretProvider(provider SecretProvider) {
+	l.secretProvider = provider
+}
+
+// resolveSecrets replaces every secret reference in the string fields of
+// config, including string slices, with the value from the SecretProvider.
+// It returns the paths of the string fields that were resolved.
+func (l *Loader) resolveSecrets(config *Config) (map[string]bool, error) {
+	resolved := make(map[string]bool)
+	for _, path := range leafFields("", reflect.TypeOf(*config)) {
+		field, ok := lookupField(config, path)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case field.Kind() == reflect.String:
+			found, err := l.resolveSecret(path, field)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				resolved[path] = true
+			}
+		case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+			for i := 0; i < field.Len(); i++ {
+				if _, err := l.resolveSecret(fmt.Sprintf("%s[%d]", path, i), field.Index(i)); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// resolveSecret resolves value in place if it holds a secret reference,
+// reporting whether it did
+func (l *Loader) resolveSecret(path string, value reflect.Value) (bool, error) {
+	ref, ok := strings.CutPrefix(value.String(), secretRefPrefix)
+	if !ok {
+		return false, nil
+	}
+	if l.secretProvider == nil {
+		return false, fmt.Errorf("%s holds a secret reference but no SecretProvider is set", path)
+	}
+
+	secret, err := l.secretProvider.GetSecret(context.Background(), ref)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve secret for %s: %w", path, err)
+	}
+	value.SetString(secret)
+	return true, nil
+}
+
+// SetSecretProvider sets the provider the manager's loader uses to resolve
+// "secret://" references on Load and Reload, before validation
+func (m *Manager) SetSecretProvider(provider SecretProvider) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.loader.SetSecretProvider(provider)
+}
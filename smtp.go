@@ -0,0 +1,108 @@
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+)
+
+// smtpsPort is the port on which SMTP servers expect implicit TLS
+const smtpsPort = 465
+
+// Accepted values of EmailConfig.Encryption
+const (
+	smtpEncryptionNone     = "none"
+	smtpEncryptionStartTLS = "starttls"
+	smtpEncryptionTLS      = "tls"
+)
+
+// smtpEncryption returns the encryption configured for config, inferring it
+// from the port when unset. The empty string stands for the inferred
+// STARTTLS, which is only used when the server offers it.
+func smtpEncryption(config EmailConfig) string {
+	if config.Encryption != "" {
+		return config.Encryption
+	}
+	if config.Port == smtpsPort {
+		return smtpEncryptionTLS
+	}
+	return ""
+}
+
+// TestEmailConnection checks the configured SMTP settings end to end: it
+// connects to the host, negotiates TLS as set by EmailConfig.Encryption
+// (by default implicit TLS on port 465, otherwise STARTTLS when the server
+// offers it) and authenticates with the configured credentials if a username
+// is set. The returned error names the stage that failed. ctx bounds the
+// whole exchange.
+func (m *Manager) TestEmailConnection(ctx context.Context) error {
+	config := m.GetEmailConfig()
+	if config.Host == "" {
+		return fmt.Errorf("email host is not configured")
+	}
+
+	address := net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+	tlsConfig := &tls.Config{ServerName: config.Host}
+	encryption := smtpEncryption(config)
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server %s: %w", address, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return fmt.Errorf("failed to set SMTP deadline: %w", err)
+		}
+	}
+	// Abort blocking reads and writes if ctx is cancelled without a deadline
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if encryption == smtpEncryptionTLS {
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return fmt.Errorf("TLS handshake with SMTP server %s failed: %w", address, err)
+		}
+		conn = tlsConn
+	}
+
+	client, err := smtp.NewClient(conn, config.Host)
+	if err != nil {
+		return fmt.Errorf("SMTP greeting from %s failed: %w", address, err)
+	}
+	defer client.Close()
+
+	if err := client.Hello("localhost"); err != nil {
+		return fmt.Errorf("SMTP EHLO to %s failed: %w", address, err)
+	}
+
+	if encryption == "" || encryption == smtpEncryptionStartTLS {
+		ok, _ := client.Extension("STARTTLS")
+		if !ok && encryption == smtpEncryptionStartTLS {
+			return fmt.Errorf("SMTP server %s does not offer STARTTLS", address)
+		}
+		if ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("SMTP STARTTLS with %s failed: %w", address, err)
+			}
+		}
+	}
+
+	if config.Username != "" {
+		auth := smtp.PlainAuth("", config.Username, config.Password, config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication as %s failed: %w", config.Username, err)
+		}
+	}
+
+	if err := client.Quit(); err != nil {
+		return fmt.Errorf("SMTP QUIT to %s failed: %w", address, err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"os"
+	"sort"
+)
+
+// CaptureEnvSnapshot returns the environment variables read by the last load
+// that were set, keyed by name. This covers every strategy: CONFIG_PATH, the
+// overrides of file keys and ${VAR} references in files are recorded too.
+// Replaying the snapshot with LoadFromSnapshot or LoadWithSnapshot reproduces
+// that configuration on another machine.
+//
+// The snapshot is not redacted: it holds secrets such as JWT_SECRET and
+// DB_PASSWORD in clear text, because replaying needs them. Store and transmit
+// it like any other credential.
+func (l *Loader) CaptureEnvSnapshot() map[string]string {
+	snap := make(map[string]string, len(l.envRead))
+	for name, value := range l.envRead {
+		snap[name] = value
+	}
+	return snap
+}
+
+// LoadFromSnapshot loads configuration like LoadFromEnvironment, but reads
+// variables from snap, as returned by CaptureEnvSnapshot, instead of the
+// process environment. Variables missing from snap are treated as unset.
+func (l *Loader) LoadFromSnapshot(snap map[string]string) (*Config, error) {
+	defer l.replaySnapshot(snap)()
+	return l.LoadFromEnvironment()
+}
+
+// LoadWithSnapshot loads configuration like Load with the given strategy, but
+// reads every environment variable, including CONFIG_PATH and the overrides
+// of file keys, from snap instead of the process environment. Files are read
+// from disk as usual.
+func (l *Loader) LoadWithSnapshot(strategy LoadStrategy, snap map[string]string) (*Config, error) {
+	defer l.replaySnapshot(snap)()
+	return l.Load(strategy)
+}
+
+// replaySnapshot makes the loader read variables from a copy of snap and
+// returns the function restoring the process environment
+func (l *Loader) replaySnapshot(snap map[string]string) func() {
+	l.envSnapshot = make(map[string]string, len(snap))
+	for name, value := range snap {
+		l.envSnapshot[name] = value
+	}
+	return func() { l.envSnapshot = nil }
+}
+
+// resetEnvRead starts recording the variables read by a new load, unless
+// the load is part of a Load call that already started recording
+func (l *Loader) resetEnvRead() {
+	if !l.loading {
+		l.envRead = make(map[string]string)
+	}
+}
+
+// getenv returns the named variable from the replayed snapshot, if any, or
+// the process environment, recording it for CaptureEnvSnapshot when set
+func (l *Loader) getenv(name string) string {
+	value, _ := l.lookupEnv(name)
+	return value
+}
+
+// lookupEnv is like getenv, but also reports whether the variable is set
+func (l *Loader) lookupEnv(name string) (string, bool) {
+	value, ok := os.LookupEnv(name)
+	if l.envSnapshot != nil {
+		value, ok = l.envSnapshot[name]
+	}
+	if value != "" {
+		l.recordEnvRead(name, value)
+	}
+	return value, ok
+}
+
+// recordEnvRead records a set variable read during a load
+func (l *Loader) recordEnvRead(name, value string) {
+	if l.envRead != nil {
+		l.envRead[name] = value
+	}
+}
+
+// environ returns the replayed snapshot, if any, or the process environment
+// as "key=value" entries
+func (l *Loader) environ() []string {
+	if l.envSnapshot == nil {
+		return os.Environ()
+	}
+
+	entries := make([]string, 0, len(l.envSnapshot))
+	for name, value := range l.envSnapshot {
+		entries = append(entries, name+"="+value)
+	}
+	sort.Strings(entries)
+	return entries
+}
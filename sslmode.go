@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SSLMode is a database SSL mode using PostgreSQL naming
+type SSLMode string
+
+// Supported SSL modes
+const (
+	SSLModeDisable    SSLMode = "disable"
+	SSLModeRequire    SSLMode = "require"
+	SSLModeVerifyCA   SSLMode = "verify-ca"
+	SSLModeVerifyFull SSLMode = "verify-full"
+)
+
+// mysqlSSLModes maps PostgreSQL SSL modes to the equivalent MySQL ssl-mode values
+var mysqlSSLModes = map[SSLMode]string{
+	SSLModeDisable:    "DISABLED",
+	SSLModeRequire:    "REQUIRED",
+	SSLModeVerifyCA:   "VERIFY_CA",
+	SSLModeVerifyFull: "VERIFY_IDENTITY",
+}
+
+// ParseSSLMode parses an SSL mode case-insensitively
+func ParseSSLMode(s string) (SSLMode, error) {
+	mode := SSLMode(strings.ToLower(s))
+	if _, ok := mysqlSSLModes[mode]; !ok {
+		return "", fmt.Errorf("invalid SSL mode %q: must be one of: %s", s, strings.Join(validSSLModes, ", "))
+	}
+	return mode, nil
+}
+
+// MySQLMode returns the equivalent MySQL ssl-mode value, e.g. "VERIFY_IDENTITY" for verify-full
+func (m SSLMode) MySQLMode() (string, error) {
+	mode, ok := mysqlSSLModes[m]
+	if !ok {
+		return "", fmt.Errorf("invalid SSL mode %q", string(m))
+	}
+	return mode, nil
+}
+
+// ParsedSSLMode returns the configured SSL mode as a typed SSLMode
+func (c DatabaseConfig) ParsedSSLMode() (SSLMode, error) {
+	return ParseSSLMode(c.SSLMode)
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// validateTags enforces the rules declared in `validate` struct tags, e.g.
+// `validate:"required,min=32"`. Rules are checked in order and only the first
+// failing rule of each field is reported. Supported rules:
+//
+//	required  the field must not be its zero value
+//	min=N     strings, slices and maps need at least N elements; numbers must be >= N
+//	max=N     strings, slices and maps may have at most N elements; numbers must be <= N
+func (v *Validator) validateTags(prefix string, value reflect.Value) {
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		path := fieldPath(prefix, field)
+		if field.Type.Kind() == reflect.Struct {
+			v.validateTags(path, value.Field(i))
+			continue
+		}
+
+		tag := field.Tag.Get("validate")
+		if tag == "" {
+			continue
+		}
+		for _, rule := range strings.Split(tag, ",") {
+			if problem := checkTagRule(value.Field(i), strings.TrimSpace(rule)); problem != "" {
+				v.addError(path, path+" "+problem)
+				break
+			}
+		}
+	}
+}
+
+// checkTagRule applies a single tag rule to value and describes the violation,
+// or returns "" if the rule holds
+func checkTagRule(value reflect.Value, rule string) string {
+	name, arg, _ := strings.Cut(rule, "=")
+	switch name {
+	case "required":
+		if value.IsZero() {
+			return "is required"
+		}
+	case "min", "max":
+		limit, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Sprintf("has an invalid %s rule: %q", name, arg)
+		}
+		size, unit, ok := tagMeasure(value)
+		if !ok {
+			return fmt.Sprintf("does not support the %s rule", name)
+		}
+		if name == "min" && size < int64(limit) {
+			return fmt.Sprintf("must be at least %d%s", limit, unit)
+		}
+		if name == "max" && size > int64(limit) {
+			return fmt.Sprintf("must be at most %d%s", limit, unit)
+		}
+	default:
+		return fmt.Sprintf("has an unknown validation rule: %q", name)
+	}
+	return ""
+}
+
+// tagMeasure returns the quantity that min and max rules compare against,
+// along with the unit used in error messages
+func tagMeasure(value reflect.Value) (int64, string, bool) {
+	switch value.Kind() {
+	case reflect.String:
+		return int64(value.Len()), " characters long", true
+	case reflect.Slice, reflect.Map:
+		return int64(value.Len()), " entries", true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), "", true
+	default:
+		return 0, "", false
+	}
+}
@@ -0,0 +1,272 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// SetTemplating enables or disables resolving Go template expressions in
+// string fields after Load, e.g. app.name: "{{ .App.Environment }}-service".
+// Templates are evaluated against the loaded Config and may reference fields
+// that are themselves templates; reference cycles are reported as errors.
+// Secret fields and values resolved by the SecretProvider are never
+// evaluated as templates, and templates must not reference them, directly
+// or by printing a section or the whole Config that holds them. Templating
+// is disabled by default.
+func (l *Loader) SetTemplating(enabled bool) {
+	l.templating = enabled
+}
+
+// resolveTemplates evaluates every string field of config holding a
+// template, except secret fields and the fields in secrets, which were
+// resolved from a SecretProvider
+func resolveTemplates(config *Config, secrets map[string]bool) error {
+	r := &templateResolver{
+		config:   config,
+		secrets:  secrets,
+		state:    make(map[string]int),
+		stack:    make([]string, 0),
+		rootType: reflect.TypeOf(*config),
+	}
+	r.leaves = leafFields("", r.rootType)
+	for _, path := range r.leaves {
+		if err := r.resolve(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Resolution states of a field during resolveTemplates
+const (
+	templateUnvisited = iota
+	templateResolving
+	templateResolved
+)
+
+// templateResolver resolves templated fields depth-first so that referenced
+// templates are evaluated before the fields that use them
+type templateResolver struct {
+	config   *Config
+	secrets  map[string]bool
+	state    map[string]int
+	stack    []string
+	rootType reflect.Type
+	leaves   []string
+}
+
+// resolve evaluates the template held by the field at path, if any
+func (r *templateResolver) resolve(path string) error {
+	switch r.state[path] {
+	case templateResolved:
+		return nil
+	case templateResolving:
+		return fmt.Errorf("template reference cycle: %s -> %s", strings.Join(r.stack, " -> "), path)
+	}
+
+	field, ok := lookupField(r.config, path)
+	if !ok || field.Kind() != reflect.String || !strings.Contains(field.String(), "{{") || isSecretField(path) || r.secrets[path] {
+		r.state[path] = templateResolved
+		return nil
+	}
+
+	r.state[path] = templateResolving
+	r.stack = append(r.stack, path)
+
+	tmpl, err := template.New(path).Option("missingkey=error").Parse(field.String())
+	if err != nil {
+		return fmt.Errorf("invalid template in %s: %w", path, err)
+	}
+	refs, err := templateReferences(tmpl.Tree.Root)
+	if err != nil {
+		return fmt.Errorf("invalid template in %s: %w", path, err)
+	}
+	for _, ref := range refs {
+		refPath, ok := r.fieldPathOf(ref.chain)
+		if !ok {
+			continue
+		}
+		if secret, found := r.secretWithin(refPath, ref.scope); found {
+			return fmt.Errorf("invalid template in %s: it must not reference the secret field %s", path, secret)
+		}
+		if err := r.resolve(refPath); err != nil {
+			return err
+		}
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, r.config); err != nil {
+		return fmt.Errorf("failed to evaluate template in %s: %w", path, err)
+	}
+	field.SetString(b.String())
+
+	r.stack = r.stack[:len(r.stack)-1]
+	r.state[path] = templateResolved
+	return nil
+}
+
+// secretWithin returns a secret field, or one resolved from a SecretProvider,
+// at path or, unless exact is set, below it. An empty path is the whole Config.
+func (r *templateResolver) secretWithin(path string, exact bool) (string, bool) {
+	for _, leaf := range r.leaves {
+		covered := leaf == path || (!exact && (path == "" || strings.HasPrefix(leaf, path+".")))
+		if covered && (isSecretField(leaf) || r.secrets[leaf]) {
+			return leaf, true
+		}
+	}
+	return "", false
+}
+
+// fieldPathOf converts a template field reference such as ["App", "Name"]
+// into its dotted config path, e.g. "app.name"
+func (r *templateResolver) fieldPathOf(ref []string) (string, bool) {
+	t := r.rootType
+	path := ""
+	for _, name := range ref {
+		if t.Kind() != reflect.Struct {
+			return path, path != ""
+		}
+		field, ok := t.FieldByName(name)
+		if !ok || !field.IsExported() {
+			return "", false
+		}
+		path = fieldPath(path, field)
+		t = field.Type
+	}
+	return path, true
+}
+
+// templateRef is a field chain referenced by a template, such as
+// [App Environment]. A scope reference, the pipeline of a with action, only
+// sets the dot for the action's body rather than being printed.
+type templateRef struct {
+	chain []string
+	scope bool
+}
+
+// templateReferences returns the field chains referenced anywhere in a
+// template parse tree, relative to the Config. Fields reached through $ or a
+// with action are followed; references that cannot be traced back to the
+// Config, such as fields of a range element or of a declared variable, are
+// reported as errors.
+func templateReferences(node parse.Node) ([]templateRef, error) {
+	w := &templateWalker{}
+	w.walk(node, []string{})
+	return w.refs, w.err
+}
+
+// templateWalker collects field references while walking a parse tree. The
+// dot of each node is the chain of fields it refers to, or nil when it is not
+// a field of the Config, as inside a range.
+type templateWalker struct {
+	refs []templateRef
+	err  error
+}
+
+func (w *templateWalker) walk(node parse.Node, dot []string) {
+	if w.err != nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			w.walk(child, dot)
+		}
+	case *parse.ActionNode:
+		w.walk(n.Pipe, dot)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			w.walk(cmd, dot)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			w.walk(arg, dot)
+		}
+	case *parse.DotNode, *parse.FieldNode, *parse.VariableNode, *parse.ChainNode:
+		if chain, ok := w.chain(n, dot); ok {
+			w.refs = append(w.refs, templateRef{chain: chain})
+		}
+	case *parse.IfNode:
+		w.walk(n.Pipe, dot)
+		w.walk(n.List, dot)
+		w.walk(n.ElseList, dot)
+	case *parse.RangeNode:
+		w.walk(n.Pipe, dot)
+		w.walk(n.List, nil)
+		w.walk(n.ElseList, dot)
+	case *parse.WithNode:
+		scope := w.pipeChain(n.Pipe, dot)
+		if scope != nil {
+			w.refs = append(w.refs, templateRef{chain: scope, scope: true})
+		} else {
+			w.walk(n.Pipe, dot)
+		}
+		w.walk(n.List, scope)
+		w.walk(n.ElseList, dot)
+	case *parse.TemplateNode:
+		w.err = fmt.Errorf("template actions are not supported: %s", n)
+	}
+}
+
+// chain returns the field chain referenced by a field, variable or chain
+// node evaluated with the given dot
+func (w *templateWalker) chain(node parse.Node, dot []string) ([]string, bool) {
+	switch n := node.(type) {
+	case *parse.DotNode:
+		return dot, dot != nil
+	case *parse.FieldNode:
+		if dot == nil {
+			w.err = fmt.Errorf("unsupported reference %s: fields inside range are not fields of the configuration", n)
+			return nil, false
+		}
+		return append(append([]string(nil), dot...), n.Ident...), true
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" {
+			return n.Ident[1:], true
+		}
+		if len(n.Ident) > 1 {
+			w.err = fmt.Errorf("unsupported reference %s: only fields of $ and . are supported", n)
+		}
+		return nil, false
+	case *parse.ChainNode:
+		base, ok := w.chain(n.Node, dot)
+		if !ok {
+			if w.err == nil {
+				w.err = fmt.Errorf("unsupported reference %s: only fields of $ and . are supported", n)
+			}
+			return nil, false
+		}
+		return append(append([]string(nil), base...), n.Field...), true
+	case *parse.PipeNode:
+		if chain := w.pipeChain(n, dot); chain != nil {
+			return chain, true
+		}
+		w.walk(n, dot)
+	}
+	return nil, false
+}
+
+// pipeChain returns the field chain a pipeline evaluates to when it is a
+// single field reference, such as .App in {{ with .App }}, or nil otherwise
+func (w *templateWalker) pipeChain(pipe *parse.PipeNode, dot []string) []string {
+	if pipe == nil || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return nil
+	}
+	switch arg := pipe.Cmds[0].Args[0].(type) {
+	case *parse.DotNode, *parse.FieldNode, *parse.VariableNode, *parse.ChainNode:
+		if chain, ok := w.chain(arg, dot); ok {
+			return chain
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/sublimeai21/config"
+)
+
+func TestContextRoundTrip(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	ctx := manager.WithContext(context.Background())
+
+	// A reload after the snapshot was taken must not affect it
+	os.Setenv("SERVER_PORT", "9090")
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Failed to reload configuration: %v", err)
+	}
+
+	cfg, ok := config.FromContext(ctx)
+	if !ok {
+		t.Fatal("Expected configuration in context")
+	}
+	if cfg.Server.Port != "8080" {
+		t.Errorf("Expected snapshot port 8080, got %s", cfg.Server.Port)
+	}
+	if manager.GetServerConfig().Port != "9090" {
+		t.Errorf("Expected manager port 9090 after reload, got %s", manager.GetServerConfig().Port)
+	}
+}
+
+func TestFromContextMissing(t *testing.T) {
+	if cfg, ok := config.FromContext(context.Background()); ok || cfg != nil {
+		t.Error("Expected no configuration in an empty context")
+	}
+
+	// An unloaded manager stores no usable snapshot
+	ctx := config.NewManager().WithContext(context.Background())
+	if _, ok := config.FromContext(ctx); ok {
+		t.Error("Expected no configuration from an unloaded manager")
+	}
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/sublimeai21/config"
+)
+
+func TestDefaultManagerIsReused(t *testing.T) {
+	config.SetDefault(nil)
+	t.Cleanup(func() { config.SetDefault(nil) })
+
+	managers := make([]*config.Manager, 8)
+	var wg sync.WaitGroup
+	for i := range managers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			managers[i] = config.Default()
+		}(i)
+	}
+	wg.Wait()
+
+	for _, m := range managers {
+		if m == nil || m != managers[0] {
+			t.Fatal("Expected every caller to get the same default manager")
+		}
+	}
+	if config.GetConfig() != nil {
+		t.Error("Expected no configuration before the default manager is loaded")
+	}
+
+	if err := config.Default().LoadConfig(validConfig()); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if got := config.GetServerConfig().Port; got != "8080" {
+		t.Errorf("Expected port 8080 from the default manager, got %q", got)
+	}
+	if got := config.GetJWTConfig().Issuer; got != validConfig().JWT.Issuer {
+		t.Errorf("Expected issuer %q from the default manager, got %q", validConfig().JWT.Issuer, got)
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	t.Cleanup(func() { config.SetDefault(nil) })
+
+	cfg := validConfig()
+	cfg.App.Name = "Replacement"
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	config.SetDefault(manager)
+	if config.Default() != manager {
+		t.Fatal("Expected SetDefault to replace the default manager")
+	}
+	if got := config.GetAppConfig().Name; got != "Replacement" {
+		t.Errorf("Expected the replacement's app name, got %q", got)
+	}
+
+	config.SetDefault(nil)
+	if config.Default() == manager {
+		t.Error("Expected SetDefault(nil) to discard the default manager")
+	}
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sublimeai21/config"
+)
+
+func TestCompareFiles(t *testing.T) {
+	clearConfigEnv(t)
+
+	pathA := writeConfigFile(t, "staging.yaml", validYAML)
+	pathB := writeConfigFile(t, "production.yaml", strings.Replace(validYAML, `port: "8080"`, `port: "9090"`, 1))
+
+	changes, err := config.CompareFiles(pathA, pathB)
+	if err != nil {
+		t.Fatalf("CompareFiles failed: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("Expected 1 change, got %d: %v", len(changes), changes)
+	}
+	if changes[0].Field != "server.port" || changes[0].OldValue != "8080" || changes[0].NewValue != "9090" {
+		t.Errorf("Unexpected change: %+v", changes[0])
+	}
+}
+
+func TestCompareFilesRedactsSecrets(t *testing.T) {
+	clearConfigEnv(t)
+
+	pathA := writeConfigFile(t, "a.yaml", validYAML)
+	pathB := writeConfigFile(t, "b.yaml", strings.Replace(validYAML, `password: "password"`, `password: "hunter2"`, 1))
+
+	changes, err := config.CompareFiles(pathA, pathB)
+	if err != nil {
+		t.Fatalf("CompareFiles failed: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Field != "database.password" {
+		t.Fatalf("Expected a single database.password change, got %v", changes)
+	}
+	if changes[0].OldValue == "password" || changes[0].NewValue == "hunter2" {
+		t.Errorf("Expected secret values to be redacted, got %+v", changes[0])
+	}
+}
+
+func TestRedactChangesRedisURL(t *testing.T) {
+	before := validConfig()
+	after := validConfig()
+	after.Redis.URL = "redis://:hunter2@cache.internal:6379/1"
+
+	changes := config.RedactChanges(config.Diff(before, after))
+	if len(changes) != 1 || changes[0].Field != "redis.url" {
+		t.Fatalf("Expected a single redis.url change, got %v", changes)
+	}
+	if changes[0].NewValue != "[REDACTED]" {
+		t.Errorf("Expected the redis URL to be redacted, got %+v", changes[0])
+	}
+}
+
+func TestCompareFilesMissingFile(t *testing.T) {
+	clearConfigEnv(t)
+
+	pathA := writeConfigFile(t, "a.yaml", validYAML)
+	if _, err := config.CompareFiles(pathA, pathA+".missing"); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestDiffAgainstFile(t *testing.T) {
+	clearConfigEnv(t)
+
+	t.Setenv("CONFIG_PATH", writeConfigFile(t, "running.yaml", validYAML))
+	manager := config.NewManager()
+	if err := manager.Load(config.FileStrategy); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	path := writeConfigFile(t, "config.yaml", strings.Replace(validYAML, `port: "8080"`, `port: "9090"`, 1))
+	changes, err := manager.DiffAgainstFile(path)
+	if err != nil {
+		t.Fatalf("DiffAgainstFile failed: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("Expected 1 change, got %d: %v", len(changes), changes)
+	}
+	if changes[0].Field != "server.port" || changes[0].OldValue != "8080" || changes[0].NewValue != "9090" {
+		t.Errorf("Unexpected change: %+v", changes[0])
+	}
+}
+
+func TestDiffAgainstFileRedactsSecrets(t *testing.T) {
+	clearConfigEnv(t)
+
+	t.Setenv("CONFIG_PATH", writeConfigFile(t, "running.yaml", validYAML))
+	manager := config.NewManager()
+	if err := manager.Load(config.FileStrategy); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	path := writeConfigFile(t, "config.yaml", strings.Replace(validYAML, `password: "password"`, `password: "hunter2"`, 1))
+	changes, err := manager.DiffAgainstFile(path)
+	if err != nil {
+		t.Fatalf("DiffAgainstFile failed: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Field != "database.password" {
+		t.Fatalf("Expected a single database.password change, got %v", changes)
+	}
+	if changes[0].OldValue != "[REDACTED]" || changes[0].NewValue != "[REDACTED]" {
+		t.Errorf("Expected secret values to be redacted, got %+v", changes[0])
+	}
+}
+
+func TestDiffAgainstFileUnloaded(t *testing.T) {
+	clearConfigEnv(t)
+
+	path := writeConfigFile(t, "config.yaml", validYAML)
+	if _, err := config.NewManager().DiffAgainstFile(path); err == nil {
+		t.Error("Expected an error when no configuration is loaded")
+	}
+}
@@ -0,0 +1,340 @@
+package config
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sublimeai21/config"
+)
+
+// parseDotEnv parses KEY=value lines as written by ExportDotEnv
+func parseDotEnv(t *testing.T, content string) map[string]string {
+	t.Helper()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			t.Fatalf("Malformed .env line: %q", scanner.Text())
+		}
+		if strings.HasPrefix(value, `"`) {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				t.Fatalf("Malformed quoted value for %s: %v", key, err)
+			}
+			value = unquoted
+		}
+		values[key] = value
+	}
+	return values
+}
+
+func TestExportDotEnvRoundTrip(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("SERVER_TIMEOUT_UPLOAD", "5m")
+	t.Setenv("APP_NAME", "My App")
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	exported := manager.ExportDotEnv()
+	if !strings.Contains(exported, "SERVER_PORT=8080\n") {
+		t.Errorf("Expected SERVER_PORT=8080 in export, got:\n%s", exported)
+	}
+	if !strings.Contains(exported, `APP_NAME="My App"`) {
+		t.Errorf("Expected quoted APP_NAME in export, got:\n%s", exported)
+	}
+
+	clearConfigEnv(t)
+	t.Setenv("SERVER_TIMEOUT_UPLOAD", "")
+	for key, value := range parseDotEnv(t, exported) {
+		t.Setenv(key, value)
+	}
+
+	reloaded, err := config.NewLoader().LoadFromEnvironment()
+	if err != nil {
+		t.Fatalf("Failed to load exported environment: %v", err)
+	}
+	if changes := config.Diff(manager.GetConfig(), reloaded); len(changes) != 0 {
+		t.Errorf("Exported environment did not reproduce the configuration: %v", changes)
+	}
+}
+
+func TestExportDotEnvEmptyFieldsRoundTrip(t *testing.T) {
+	clearConfigEnv(t)
+
+	// Empty fields whose variables default to something else
+	cfg := validConfig()
+	cfg.Database.DBWritePort = ""
+	cfg.Database.DBReadPort = ""
+	cfg.Database.DatabaseConfigType = ""
+	// APP_ENVIRONMENT sets both environments
+	cfg.Database.Environment = cfg.App.Environment
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+
+	exported := manager.ExportDotEnv()
+	for _, line := range []string{`DB_WRITE_PORT=""`, `DB_READ_PORT=""`, `DATABASE_CONFIG_TYPE=""`} {
+		if !strings.Contains(exported, line+"\n") {
+			t.Errorf("Expected %s in export, got:\n%s", line, exported)
+		}
+	}
+	if !strings.Contains(exported, "DB_WRITE_HOST=\n") {
+		t.Errorf("Expected empty fields defaulting to empty to stay bare, got:\n%s", exported)
+	}
+
+	reimported := config.NewManager()
+	if err := reimported.LoadDotEnv(writeConfigFile(t, ".env", exported)); err != nil {
+		t.Fatalf("Failed to load exported .env file: %v", err)
+	}
+	if err := reimported.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load exported environment: %v", err)
+	}
+	if changes := config.Diff(manager.GetConfig(), reimported.GetConfig()); len(changes) != 0 {
+		t.Errorf("Exported .env file did not reproduce the configuration: %v", changes)
+	}
+	if got := reimported.ExportDotEnv(); got != exported {
+		t.Errorf("Expected a second export to match the first, got:\n%s", got)
+	}
+
+	// Without a quoted empty value, the default applies as before
+	t.Setenv("DB_WRITE_PORT", "")
+	cfg2, err := config.NewLoader().LoadFromEnvironment()
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg2.Database.DBWritePort != "5432" {
+		t.Errorf("Expected an unset DB_WRITE_PORT to default to 5432, got %q", cfg2.Database.DBWritePort)
+	}
+}
+
+func TestExportDotEnvDurationRoundTrip(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"0.5s", 500 * time.Millisecond},
+		{"1.25s", 1250 * time.Millisecond},
+		{"0.25ms", 250 * time.Microsecond},
+		{"750us", 750 * time.Microsecond},
+		{"1.5µs", 1500 * time.Nanosecond},
+		{"0.3d", 7*time.Hour + 12*time.Minute},
+		{"1.5d12h", 48 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			setValidEnv(t)
+			t.Setenv("JWT_EXPIRATION", tt.value)
+			t.Setenv("SERVER_TIMEOUT_UPLOAD", tt.value)
+
+			manager := config.NewManager()
+			if err := manager.Load(config.EnvironmentStrategy); err != nil {
+				t.Fatalf("Failed to load configuration: %v", err)
+			}
+			loaded := manager.GetConfig()
+			if loaded.JWT.Expiration != tt.want || loaded.Server.Timeouts["upload"] != tt.want {
+				t.Fatalf("Expected %s, got %s and %s", tt.want, loaded.JWT.Expiration, loaded.Server.Timeouts["upload"])
+			}
+
+			exported := manager.ExportDotEnv()
+			clearConfigEnv(t)
+			t.Setenv("SERVER_TIMEOUT_UPLOAD", "")
+			for key, value := range parseDotEnv(t, exported) {
+				t.Setenv(key, value)
+			}
+
+			reloaded, err := config.NewLoader().LoadFromEnvironment()
+			if err != nil {
+				t.Fatalf("Failed to load exported environment: %v", err)
+			}
+			if reloaded.JWT.Expiration != tt.want || reloaded.Server.Timeouts["upload"] != tt.want {
+				t.Errorf("Expected %s after reload, got %s and %s", tt.want, reloaded.JWT.Expiration, reloaded.Server.Timeouts["upload"])
+			}
+		})
+	}
+}
+
+func TestExportDotEnvAppEnvironment(t *testing.T) {
+	clearConfigEnv(t)
+	content := strings.NewReplacer(
+		`environment: "test"`, `environment: "production"`,
+		`idle_timeout: "60s"`, `idle_timeout: "60s"
+  tls_cert_file: "/etc/app/tls.crt"
+  tls_key_file: "/etc/app/tls.key"`,
+	).Replace(validYAML) + `
+email:
+  host: "smtp.example.com"
+  port: 587
+  username: "user"
+  from: "noreply@example.com"
+`
+	t.Setenv("CONFIG_PATH", writeConfigFile(t, "config.yaml", content))
+
+	manager := config.NewManager()
+	if err := manager.Load(config.FileStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	cfg := manager.GetConfig()
+	if cfg.App.Environment != "production" || cfg.Database.Environment == "production" {
+		t.Fatalf("Expected only app.environment to be production, got app %q, database %q", cfg.App.Environment, cfg.Database.Environment)
+	}
+
+	if exported := manager.ExportDotEnv(); !strings.Contains(exported, "APP_ENVIRONMENT=production\n") {
+		t.Errorf("Expected APP_ENVIRONMENT=production in export, got:\n%s", exported)
+	}
+	found := false
+	for _, entry := range manager.EnvironSlice() {
+		if strings.HasPrefix(entry, "APP_ENVIRONMENT=") {
+			found = true
+			if entry != "APP_ENVIRONMENT=production" {
+				t.Errorf("Expected APP_ENVIRONMENT=production in EnvironSlice, got %s", entry)
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected APP_ENVIRONMENT in EnvironSlice")
+	}
+}
+
+func TestExportDotEnvRedacted(t *testing.T) {
+	cfg := validConfig()
+	cfg.Redis.URL = "redis://:hunter2@cache.internal:6379/1"
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+
+	values := parseDotEnv(t, manager.ExportDotEnvRedacted())
+	if got := values["JWT_SECRET"]; got != "[REDACTED]" {
+		t.Errorf("Expected JWT_SECRET to be redacted, got %q", got)
+	}
+	if got := values["REDIS_URL"]; got != "[REDACTED]" {
+		t.Errorf("Expected REDIS_URL to be redacted, got %q", got)
+	}
+	if got := values["SERVER_PORT"]; got != "8080" {
+		t.Errorf("Expected SERVER_PORT=8080, got %q", got)
+	}
+}
+
+func TestLoadDotEnv(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("SERVER_PORT", "")
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("APP_NAME", "")
+	t.Setenv("DB_PASSWORD", "")
+	t.Setenv("JWT_ISSUER", "")
+	t.Setenv("REDIS_HOST", "from-environment")
+
+	path := writeConfigFile(t, ".env", `# Local overrides
+SERVER_PORT=9090 # inline comment
+export LOG_LEVEL=debug
+
+APP_NAME="My \"Quoted\" App"
+DB_PASSWORD='p#ss $word'
+JWT_ISSUER = auth-service
+REDIS_HOST=from-file
+`)
+
+	loader := config.NewLoader()
+	if err := loader.LoadDotEnv(path); err != nil {
+		t.Fatalf("Failed to load .env file: %v", err)
+	}
+	cfg, err := loader.LoadFromEnvironment()
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	if cfg.Server.Port != "9090" {
+		t.Errorf("Expected server port 9090, got %q", cfg.Server.Port)
+	}
+	if cfg.Log.Level != "debug" {
+		t.Errorf("Expected log level debug, got %q", cfg.Log.Level)
+	}
+	if cfg.App.Name != `My "Quoted" App` {
+		t.Errorf("Expected unquoted app name, got %q", cfg.App.Name)
+	}
+	if cfg.Database.Password != "p#ss $word" {
+		t.Errorf("Expected literal single-quoted password, got %q", cfg.Database.Password)
+	}
+	if cfg.JWT.Issuer != "auth-service" {
+		t.Errorf("Expected JWT issuer auth-service, got %q", cfg.JWT.Issuer)
+	}
+	if cfg.Redis.Host != "from-environment" {
+		t.Errorf("Expected existing environment to take precedence, got %q", cfg.Redis.Host)
+	}
+}
+
+func TestLoadDotEnvErrors(t *testing.T) {
+	tests := map[string]string{
+		"missing equals":   "SERVER_PORT\n",
+		"unterminated":     "APP_NAME=\"My App\n",
+		"trailing garbage": "APP_NAME='My App' extra\n",
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := writeConfigFile(t, ".env", content)
+			if err := config.NewLoader().LoadDotEnv(path); err == nil {
+				t.Error("Expected parse error")
+			}
+		})
+	}
+
+	if err := config.NewLoader().LoadDotEnv("does-not-exist.env"); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+func TestEnvironSlice(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("SERVER_PORT", "9191")
+	t.Setenv("SERVER_TIMEOUT_UPLOAD", "5m")
+
+	manager := config.NewManager()
+	if environ := manager.EnvironSlice(); environ != nil {
+		t.Errorf("Expected no entries before a load, got %v", environ)
+	}
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	environ := manager.EnvironSlice()
+	want := map[string]bool{"SERVER_PORT=9191": false, "SERVER_TIMEOUT_UPLOAD=5m0s": false}
+	for _, entry := range environ {
+		if _, ok := want[entry]; ok {
+			want[entry] = true
+		}
+	}
+	for entry, found := range want {
+		if !found {
+			t.Errorf("Expected %q in %v", entry, environ)
+		}
+	}
+
+	// A child process sees only the exported entries
+	clearConfigEnv(t)
+	os.Unsetenv("SERVER_TIMEOUT_UPLOAD")
+	for _, entry := range environ {
+		name, value, _ := strings.Cut(entry, "=")
+		t.Setenv(name, value)
+	}
+
+	child, err := config.NewLoader().LoadFromEnvironment()
+	if err != nil {
+		t.Fatalf("Failed to load configuration in child: %v", err)
+	}
+	if changes := config.Diff(manager.GetConfig(), child); len(changes) != 0 {
+		t.Errorf("Expected the child to load an identical configuration, got changes %v", changes)
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sublimeai21/config"
+)
+
+func TestWriteExampleConfigYAML(t *testing.T) {
+	clearConfigEnv(t)
+
+	path := filepath.Join(t.TempDir(), "example.yaml")
+	if err := config.WriteExampleConfig(path, "yaml"); err != nil {
+		t.Fatalf("WriteExampleConfig failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read example config: %v", err)
+	}
+	if !strings.Contains(string(data), "# Port the HTTP server listens on (env: SERVER_PORT)") {
+		t.Error("Expected a descriptive comment for server.port")
+	}
+
+	cfg, err := config.NewLoader().LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("Generated YAML does not parse: %v", err)
+	}
+	if cfg.JWT.Secret == "" || !strings.Contains(cfg.JWT.Secret, "change-me") {
+		t.Errorf("Expected a jwt.secret placeholder, got %q", cfg.JWT.Secret)
+	}
+	if cfg.Server.Port != "8080" || cfg.Server.ReadTimeout.String() != "30s" {
+		t.Errorf("Expected default server values, got %+v", cfg.Server)
+	}
+	if err := config.NewValidator().Validate(cfg); err != nil {
+		t.Errorf("Example config should validate: %v", err)
+	}
+}
+
+func TestWriteExampleConfigJSON(t *testing.T) {
+	clearConfigEnv(t)
+
+	path := filepath.Join(t.TempDir(), "example.json")
+	if err := config.WriteExampleConfig(path, "json"); err != nil {
+		t.Fatalf("WriteExampleConfig failed: %v", err)
+	}
+
+	cfg, err := config.NewLoader().LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("Generated JSON does not parse: %v", err)
+	}
+	if cfg.Email.Port != 587 {
+		t.Errorf("Expected email port 587, got %d", cfg.Email.Port)
+	}
+}
+
+func TestWriteExampleConfigUnsupportedFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "example.ini")
+	if err := config.WriteExampleConfig(path, "ini"); err == nil {
+		t.Error("Expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,205 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sublimeai21/config"
+)
+
+func TestHealthCheckPerDependencyTimeouts(t *testing.T) {
+	manager := config.NewManager()
+
+	manager.AddHealthCheck("fast", time.Second, func(ctx context.Context) error {
+		return nil
+	})
+	hanging := make(chan struct{})
+	defer close(hanging)
+	manager.AddHealthCheck("slow", 50*time.Millisecond, func(ctx context.Context) error {
+		// Ignores ctx to simulate a dependency client that hangs
+		<-hanging
+		return nil
+	})
+
+	start := time.Now()
+	results := manager.HealthCheck(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("HealthCheck should return promptly, took %s", elapsed)
+	}
+
+	if err, ok := results["fast"]; !ok || err != nil {
+		t.Errorf("Expected fast dependency to be healthy, got %v (present=%t)", err, ok)
+	}
+	if err := results["slow"]; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected slow dependency to report context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestHealthCheckBuiltInDependencies(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start listener: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to reserve port: %v", err)
+	}
+	closedPort := strconv.Itoa(closed.Addr().(*net.TCPAddr).Port)
+	closed.Close()
+
+	cfg := validConfig()
+	cfg.Redis.Host = "127.0.0.1"
+	cfg.Redis.Port = strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+	cfg.Database.Host = "127.0.0.1"
+	cfg.Database.Port = closedPort
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+	manager.SetHealthCheckTimeout("database", 500*time.Millisecond)
+
+	results := manager.HealthCheck(context.Background())
+	if err, ok := results["redis"]; !ok || err != nil {
+		t.Errorf("Expected redis to be healthy, got %v (present=%t)", err, ok)
+	}
+	if results["database"] == nil {
+		t.Error("Expected database check to fail against a closed port")
+	}
+	if _, ok := results["email"]; ok {
+		t.Error("Email should not be checked when no host is configured")
+	}
+}
+
+// acceptingListener starts a TCP listener that accepts and closes connections
+// for the duration of the test, returning its port
+func acceptingListener(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start listener: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	return strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+}
+
+func TestReady(t *testing.T) {
+	cfg := validConfig()
+	cfg.Database.Host = "127.0.0.1"
+	cfg.Database.Port = acceptingListener(t)
+	cfg.Redis.Host = "127.0.0.1"
+	cfg.Redis.Port = acceptingListener(t)
+
+	manager := config.NewManager()
+	if err := manager.Ready(context.Background()); err == nil {
+		t.Error("Expected manager without configuration not to be ready")
+	}
+
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+	if err := manager.Ready(context.Background()); err != nil {
+		t.Errorf("Expected manager to be ready, got %v", err)
+	}
+}
+
+func TestReadyUnreachableDatabase(t *testing.T) {
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to reserve port: %v", err)
+	}
+	closedPort := strconv.Itoa(closed.Addr().(*net.TCPAddr).Port)
+	closed.Close()
+
+	cfg := validConfig()
+	cfg.Database.Host = "127.0.0.1"
+	cfg.Database.Port = closedPort
+	cfg.Redis.Host = "127.0.0.1"
+	cfg.Redis.Port = acceptingListener(t)
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+	manager.SetHealthCheckTimeout("database", 500*time.Millisecond)
+
+	err = manager.Ready(context.Background())
+	if err == nil {
+		t.Fatal("Expected manager with an unreachable database not to be ready")
+	}
+	if !strings.Contains(err.Error(), "database") || strings.Contains(err.Error(), "redis") {
+		t.Errorf("Expected only the database to be reported, got %v", err)
+	}
+}
+
+func TestValidateConnectivity(t *testing.T) {
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to reserve port: %v", err)
+	}
+	closedPort := strconv.Itoa(closed.Addr().(*net.TCPAddr).Port)
+	closed.Close()
+
+	cfg := validConfig()
+	cfg.Database.Host = "127.0.0.1"
+	cfg.Database.Port = acceptingListener(t)
+	cfg.Redis.Host = "127.0.0.1"
+	cfg.Redis.Port = closedPort
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+	manager.SetHealthCheckTimeout("redis", 500*time.Millisecond)
+
+	// Off by default: an unreachable dependency does not fail validation
+	if err := manager.ValidateCurrent(); err != nil {
+		t.Errorf("Expected validation without connectivity checks to pass, got %v", err)
+	}
+
+	manager.SetValidateConnectivity(true)
+	err = manager.ValidateCurrent()
+	if err == nil {
+		t.Fatal("Expected validation to fail for an unreachable Redis")
+	}
+	if !strings.Contains(err.Error(), "redis") || strings.Contains(err.Error(), "database") {
+		t.Errorf("Expected only Redis to be reported, got %v", err)
+	}
+	if manager.IsValid() {
+		t.Error("Expected IsValid to include connectivity checks")
+	}
+
+	cfg.Redis.Port = acceptingListener(t)
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+	if err := manager.ValidateCurrent(); err != nil {
+		t.Errorf("Expected validation with reachable dependencies to pass, got %v", err)
+	}
+}
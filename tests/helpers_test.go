@@ -0,0 +1,195 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sublimeai21/config"
+)
+
+// configEnvKeys lists the environment variables the tests manipulate
+var configEnvKeys = []string{
+	"CONFIG_PATH",
+	"SERVER_PORT", "SERVER_HOST", "SERVER_READ_TIMEOUT", "SERVER_WRITE_TIMEOUT", "SERVER_IDLE_TIMEOUT",
+	"SERVER_MAX_HEADER_BYTES", "SERVER_TLS_CERT_FILE", "SERVER_TLS_KEY_FILE", "SERVER_MIN_TLS_VERSION", "SERVER_BIND_ALL",
+	"DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME", "DB_SSL_MODE", "DB_MAX_CONNS", "DB_TYPE",
+	"DB_WRITE_HOST", "DB_WRITE_PORT", "DB_WRITE_USER", "DB_WRITE_PASSWORD", "DB_WRITE_NAME",
+	"DB_READ_HOST", "DB_READ_PORT", "DB_READ_USER", "DB_READ_PASSWORD", "DB_READ_NAME", "DB_READ_REPLICAS",
+	"DATABASE_CONFIG_TYPE",
+	"DB_MAX_IDLE_CONNS", "DB_CONN_MAX_LIFETIME", "DB_CONN_MAX_IDLE_TIME",
+	"REDIS_HOST", "REDIS_PORT", "REDIS_PASSWORD", "REDIS_DB", "REDIS_POOL_SIZE", "REDIS_MIN_IDLE_CONNS",
+	"LOG_LEVEL", "LOG_FORMAT", "LOG_OUTPUT_PATH",
+	"JWT_SECRET", "JWT_EXPIRATION", "JWT_ISSUER", "JWT_ALGORITHM", "JWT_PRIVATE_KEY_PATH", "JWT_PUBLIC_KEY_PATH",
+	"EMAIL_HOST", "EMAIL_PORT", "SMTP_ENCRYPTION", "EMAIL_USERNAME", "EMAIL_PASSWORD", "EMAIL_FROM", "EMAIL_ALLOWED_FROM_DOMAINS",
+	"APP_NAME", "APP_ENVIRONMENT", "APP_VERSION", "APP_DEBUG", "APP_MAINTENANCE_MODE",
+	"RUNTIME_MAX_PROCS", "RUNTIME_MEMORY_LIMIT",
+}
+
+// clearConfigEnv blanks every configuration environment variable so that
+// file-based loads are not overridden. Values are restored when the test finishes.
+func clearConfigEnv(t testing.TB) {
+	t.Helper()
+
+	for _, key := range configEnvKeys {
+		t.Setenv(key, "")
+	}
+}
+
+// setValidEnv sets a complete, valid environment for EnvironmentStrategy loads.
+// Values are restored when the test finishes.
+func setValidEnv(t testing.TB) {
+	t.Helper()
+
+	clearConfigEnv(t)
+	env := map[string]string{
+		"SERVER_PORT":     "8080",
+		"SERVER_HOST":     "0.0.0.0",
+		"DB_HOST":         "localhost",
+		"DB_PORT":         "5432",
+		"DB_USER":         "postgres",
+		"DB_PASSWORD":     "password",
+		"DB_NAME":         "testdb",
+		"DB_SSL_MODE":     "disable",
+		"REDIS_HOST":      "localhost",
+		"REDIS_PORT":      "6379",
+		"LOG_LEVEL":       "info",
+		"LOG_FORMAT":      "json",
+		"JWT_SECRET":      "test-secret-that-is-long-enough-for-validation",
+		"JWT_ISSUER":      "testapp",
+		"APP_NAME":        "Test App",
+		"APP_ENVIRONMENT": "test",
+		"APP_VERSION":     "1.0.0",
+	}
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+}
+
+// validConfig returns a fully-populated configuration that passes validation
+func validConfig() *config.Config {
+	return &config.Config{
+		Server: config.ServerConfig{
+			Port:         "8080",
+			Host:         "0.0.0.0",
+			ReadTimeout:  30 * time.Second,
+			WriteTimeout: 30 * time.Second,
+			IdleTimeout:  60 * time.Second,
+		},
+		Database: config.DatabaseConfig{
+			Host:     "localhost",
+			Port:     "5432",
+			User:     "postgres",
+			Password: "password",
+			DBName:   "testdb",
+			SSLMode:  "disable",
+			MaxConns: 10,
+		},
+		Redis: config.RedisConfig{
+			Host: "localhost",
+			Port: "6379",
+		},
+		Log: config.LogConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		JWT: config.JWTConfig{
+			Secret:     "test-secret-that-is-long-enough-for-validation",
+			Expiration: 24 * time.Hour,
+			Issuer:     "testapp",
+		},
+		App: config.AppConfig{
+			Name:        "Test App",
+			Environment: "test",
+			Version:     "1.0.0",
+		},
+	}
+}
+
+// channelWatcher forwards every notification to a buffered channel
+type channelWatcher struct {
+	changes chan *config.Config
+}
+
+func newChannelWatcher() *channelWatcher {
+	return &channelWatcher{changes: make(chan *config.Config, 16)}
+}
+
+func (w *channelWatcher) OnConfigChanged(oldConfig, newConfig *config.Config) {
+	w.changes <- newConfig
+}
+
+// waitForChange returns the next notified config, or nil if none arrives in time
+func (w *channelWatcher) waitForChange(timeout time.Duration) *config.Config {
+	select {
+	case c := <-w.changes:
+		return c
+	case <-time.After(timeout):
+		return nil
+	}
+}
+
+// writeConfigFile writes content to a file in a temporary directory and returns its path
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+// hasFieldError reports whether err is a validation error naming field
+func hasFieldError(err error, field string) bool {
+	var validationErr *config.ValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+	for _, fe := range validationErr.Fields {
+		if fe.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// validYAML is a complete configuration file that passes validation
+const validYAML = `
+server:
+  port: "8080"
+  host: "0.0.0.0"
+  read_timeout: "30s"
+  write_timeout: "30s"
+  idle_timeout: "60s"
+
+database:
+  host: "localhost"
+  port: "5432"
+  user: "postgres"
+  password: "password"
+  dbname: "testdb"
+  sslmode: "disable"
+  max_conns: 10
+
+redis:
+  host: "localhost"
+  port: "6379"
+  db: 0
+
+log:
+  level: "info"
+  format: "json"
+
+jwt:
+  secret: "test-secret-that-is-long-enough-for-validation"
+  expiration: "24h"
+  issuer: "testapp"
+
+app:
+  name: "Test Application"
+  environment: "test"
+  version: "1.0.0"
+`
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sublimeai21/config"
+)
+
+// writeTLSFiles writes a self-signed certificate and its key as PEM files,
+// returning their paths
+func writeTLSFiles(t *testing.T) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to encode key: %v", err)
+	}
+
+	certPath := writeConfigFile(t, "tls.crt", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})))
+	keyPath := writeConfigFile(t, "tls.key", string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})))
+	return certPath, keyPath
+}
+
+func TestNewHTTPServer(t *testing.T) {
+	cfg := validConfig()
+	cfg.Server.MaxHeaderBytes = 64 << 10
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	handler := http.NewServeMux()
+	server, err := manager.NewHTTPServer(handler)
+	if err != nil {
+		t.Fatalf("NewHTTPServer failed: %v", err)
+	}
+
+	if server.Addr != "0.0.0.0:8080" {
+		t.Errorf("Expected address 0.0.0.0:8080, got %s", server.Addr)
+	}
+	if server.Handler != handler {
+		t.Error("Expected the handler to be set")
+	}
+	if server.ReadTimeout != cfg.Server.ReadTimeout || server.WriteTimeout != cfg.Server.WriteTimeout || server.IdleTimeout != cfg.Server.IdleTimeout {
+		t.Errorf("Unexpected timeouts: read %s, write %s, idle %s", server.ReadTimeout, server.WriteTimeout, server.IdleTimeout)
+	}
+	if server.MaxHeaderBytes != 64<<10 {
+		t.Errorf("Expected max header bytes %d, got %d", 64<<10, server.MaxHeaderBytes)
+	}
+	if server.TLSConfig != nil {
+		t.Error("Expected no TLS config when TLS is not enabled")
+	}
+}
+
+func TestNewHTTPServerIPv6Host(t *testing.T) {
+	cfg := validConfig()
+	cfg.Server.Host = "::1"
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	server, err := manager.NewHTTPServer(http.NewServeMux())
+	if err != nil {
+		t.Fatalf("NewHTTPServer failed: %v", err)
+	}
+	if server.Addr != "[::1]:8080" {
+		t.Errorf("Expected address [::1]:8080, got %s", server.Addr)
+	}
+	if got := manager.GetServerAddr(); got != "[::1]:8080" {
+		t.Errorf("Expected server address [::1]:8080, got %s", got)
+	}
+}
+
+func TestNewHTTPServerTLS(t *testing.T) {
+	certPath, keyPath := writeTLSFiles(t)
+
+	cfg := validConfig()
+	cfg.Server.TLSCertFile = certPath
+	cfg.Server.TLSKeyFile = keyPath
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	server, err := manager.NewHTTPServer(http.NewServeMux())
+	if err != nil {
+		t.Fatalf("NewHTTPServer failed: %v", err)
+	}
+	if server.TLSConfig == nil || len(server.TLSConfig.Certificates) != 1 {
+		t.Fatalf("Expected a TLS config with one certificate, got %+v", server.TLSConfig)
+	}
+
+	want, _ := tls.LoadX509KeyPair(certPath, keyPath)
+	if string(server.TLSConfig.Certificates[0].Certificate[0]) != string(want.Certificate[0]) {
+		t.Error("Expected the configured certificate")
+	}
+}
+
+func TestMinTLSVersion(t *testing.T) {
+	certPath, keyPath := writeTLSFiles(t)
+
+	tests := []struct {
+		version string
+		want    uint16
+	}{
+		{"", 0},
+		{"1.2", tls.VersionTLS12},
+		{"1.3", tls.VersionTLS13},
+	}
+
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Server.TLSCertFile = certPath
+		cfg.Server.TLSKeyFile = keyPath
+		cfg.Server.MinTLSVersion = tt.version
+
+		manager := config.NewManager()
+		if err := manager.LoadConfig(cfg); err != nil {
+			t.Fatalf("%q: LoadConfig failed: %v", tt.version, err)
+		}
+		tlsConfig, err := manager.GetTLSConfig()
+		if err != nil {
+			t.Fatalf("%q: GetTLSConfig failed: %v", tt.version, err)
+		}
+		if tlsConfig.MinVersion != tt.want {
+			t.Errorf("%q: expected MinVersion %#x, got %#x", tt.version, tt.want, tlsConfig.MinVersion)
+		}
+	}
+
+	cfg := validConfig()
+	cfg.Server.MinTLSVersion = "1.4"
+	err := config.NewValidator().Validate(cfg)
+	if !hasFieldError(err, "server.min_tls_version") || !strings.Contains(err.Error(), "1.2") {
+		t.Errorf("Expected a min TLS version error listing the valid versions, got: %v", err)
+	}
+}
+
+func TestNewHTTPServerErrors(t *testing.T) {
+	if _, err := config.NewManager().NewHTTPServer(nil); err == nil {
+		t.Error("Expected an error when no configuration is loaded")
+	}
+
+	cfg := validConfig()
+	cfg.Server.TLSCertFile = writeConfigFile(t, "tls.crt", "not a certificate")
+	cfg.Server.TLSKeyFile = cfg.Server.TLSCertFile
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if _, err := manager.NewHTTPServer(nil); err == nil {
+		t.Error("Expected an error for an invalid certificate")
+	}
+
+	cfg = validConfig()
+	cfg.Server.TLSCertFile = "/etc/app/tls.crt"
+	if err := config.NewValidator().Validate(cfg); !hasFieldError(err, "server.tls_key_file") {
+		t.Errorf("Expected a missing TLS key file error, got %v", err)
+	}
+}
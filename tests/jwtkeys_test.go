@@ -0,0 +1,170 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/sublimeai21/config"
+)
+
+// writeRSAKeyFiles generates an RSA key pair and writes it as PEM files,
+// returning the private and public key paths and the generated key
+func writeRSAKeyFiles(t *testing.T) (string, string, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("Failed to encode RSA public key: %v", err)
+	}
+
+	privatePath := writeConfigFile(t, "jwt.key", string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})))
+	publicPath := writeConfigFile(t, "jwt.pub", string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})))
+	return privatePath, publicPath, key
+}
+
+// rsaJWTConfig returns a valid configuration signing with RS256
+func rsaJWTConfig(privatePath, publicPath string) *config.Config {
+	cfg := validConfig()
+	cfg.JWT.Algorithm = "RS256"
+	cfg.JWT.PrivateKeyPath = privatePath
+	cfg.JWT.PublicKeyPath = publicPath
+	return cfg
+}
+
+func TestJWTKeys(t *testing.T) {
+	privatePath, publicPath, key := writeRSAKeyFiles(t)
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(rsaJWTConfig(privatePath, publicPath)); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	private, err := manager.GetJWTPrivateKey()
+	if err != nil {
+		t.Fatalf("GetJWTPrivateKey failed: %v", err)
+	}
+	if rsaKey, ok := private.(*rsa.PrivateKey); !ok || !rsaKey.Equal(key) {
+		t.Errorf("Expected the generated RSA private key, got %T", private)
+	}
+
+	public, err := manager.GetJWTPublicKey()
+	if err != nil {
+		t.Fatalf("GetJWTPublicKey failed: %v", err)
+	}
+	if rsaKey, ok := public.(*rsa.PublicKey); !ok || !rsaKey.Equal(&key.PublicKey) {
+		t.Errorf("Expected the generated RSA public key, got %T", public)
+	}
+
+	again, _ := manager.GetJWTPublicKey()
+	if again != public {
+		t.Error("Expected the parsed public key to be cached")
+	}
+}
+
+func TestJWTSecretNotRequiredForKeyPairs(t *testing.T) {
+	privatePath, publicPath, _ := writeRSAKeyFiles(t)
+
+	for _, secret := range []string{"", "short", "your-secret-key"} {
+		cfg := rsaJWTConfig(privatePath, publicPath)
+		cfg.JWT.Secret = secret
+		cfg.App.Environment = "production"
+		cfg.Email = config.EmailConfig{Host: "smtp.example.com", Port: 587, Username: "user", From: "noreply@example.com"}
+		cfg.Server.TLSCertFile, cfg.Server.TLSKeyFile = "/etc/app/tls.crt", "/etc/app/tls.key"
+
+		if err := config.NewValidator().Validate(cfg); err != nil {
+			t.Errorf("Expected RS256 configuration with secret %q to be valid, got %v", secret, err)
+		}
+	}
+}
+
+func TestJWTPublicKeyDerivedFromPrivateKey(t *testing.T) {
+	privatePath, _, key := writeRSAKeyFiles(t)
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(rsaJWTConfig(privatePath, "")); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	public, err := manager.GetJWTPublicKey()
+	if err != nil {
+		t.Fatalf("GetJWTPublicKey failed: %v", err)
+	}
+	if rsaKey, ok := public.(*rsa.PublicKey); !ok || !rsaKey.Equal(&key.PublicKey) {
+		t.Errorf("Expected the public half of the private key, got %T", public)
+	}
+}
+
+func TestJWTKeysWithSharedSecret(t *testing.T) {
+	manager := config.NewManager()
+	if err := manager.LoadConfig(validConfig()); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if _, err := manager.GetJWTPrivateKey(); err == nil {
+		t.Error("Expected an error for an HS256 configuration")
+	}
+}
+
+func TestJWTKeyValidation(t *testing.T) {
+	privatePath, publicPath, _ := writeRSAKeyFiles(t)
+	_, otherPublicPath, _ := writeRSAKeyFiles(t)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate ECDSA key: %v", err)
+	}
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatalf("Failed to encode ECDSA key: %v", err)
+	}
+	ecPath := writeConfigFile(t, "ec.key", string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER})))
+
+	tests := []struct {
+		name   string
+		modify func(cfg *config.Config)
+		field  string
+	}{
+		{"unknown algorithm", func(cfg *config.Config) { cfg.JWT.Algorithm = "none" }, "jwt.algorithm"},
+		{"no key paths", func(cfg *config.Config) { cfg.JWT.PrivateKeyPath, cfg.JWT.PublicKeyPath = "", "" }, "jwt.private_key_path"},
+		{"missing key file", func(cfg *config.Config) { cfg.JWT.PrivateKeyPath += ".missing" }, "jwt.private_key_path"},
+		{"not PEM", func(cfg *config.Config) { cfg.JWT.PublicKeyPath = writeConfigFile(t, "bad.pub", "not a key") }, "jwt.public_key_path"},
+		{"RSA key with ES256", func(cfg *config.Config) { cfg.JWT.Algorithm = "ES256" }, "jwt.private_key_path"},
+		{"ECDSA key with RS256", func(cfg *config.Config) { cfg.JWT.PrivateKeyPath, cfg.JWT.PublicKeyPath = ecPath, "" }, "jwt.private_key_path"},
+		{"ECDSA key with ES384", func(cfg *config.Config) {
+			cfg.JWT.Algorithm = "ES384"
+			cfg.JWT.PrivateKeyPath, cfg.JWT.PublicKeyPath = ecPath, ""
+		}, "jwt.private_key_path"},
+		{"mismatched pair", func(cfg *config.Config) { cfg.JWT.PublicKeyPath = otherPublicPath }, "jwt.public_key_path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := rsaJWTConfig(privatePath, publicPath)
+			tt.modify(cfg)
+
+			err := config.NewValidator().Validate(cfg)
+			if err == nil {
+				t.Fatal("Expected a validation error")
+			}
+			if !strings.Contains(err.Error(), "JWT") || !hasFieldError(err, tt.field) {
+				t.Errorf("Expected a JWT error for %s, got: %v", tt.field, err)
+			}
+		})
+	}
+
+	cfg := rsaJWTConfig(ecPath, "")
+	cfg.JWT.Algorithm = "ES256"
+	if err := config.NewValidator().Validate(cfg); err != nil {
+		t.Errorf("Expected ES256 with a P-256 key to be valid, got: %v", err)
+	}
+}
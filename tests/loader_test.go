@@ -0,0 +1,1671 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/sublimeai21/config"
+)
+
+func TestDebugBoolTokens(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected bool
+	}{
+		{"true", true},
+		{"1", true},
+		{"yes", true},
+		{"y", true},
+		{"on", true},
+		{"enabled", true},
+		{"ENABLED", true},
+		{"false", false},
+		{"0", false},
+		{"no", false},
+		{"n", false},
+		{"off", false},
+		{"disabled", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			setValidEnv(t)
+			t.Setenv("APP_DEBUG", tt.value)
+
+			loader := config.NewLoader()
+			loader.SetStrict(true)
+			cfg, err := loader.LoadFromEnvironment()
+			if err != nil {
+				t.Fatalf("Failed to load configuration: %v", err)
+			}
+			if cfg.App.Debug != tt.expected {
+				t.Errorf("APP_DEBUG=%s: expected %t, got %t", tt.value, tt.expected, cfg.App.Debug)
+			}
+		})
+	}
+}
+
+func TestDebugBoolInvalidToken(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("APP_DEBUG", "sometimes")
+
+	// Non-strict mode keeps the default
+	cfg, err := config.NewLoader().LoadFromEnvironment()
+	if err != nil {
+		t.Fatalf("Non-strict load should not fail: %v", err)
+	}
+	if cfg.App.Debug {
+		t.Error("Expected invalid APP_DEBUG to fall back to false")
+	}
+
+	// Strict mode surfaces the error
+	loader := config.NewLoader()
+	loader.SetStrict(true)
+	if _, err := loader.LoadFromEnvironment(); err == nil {
+		t.Error("Strict load should fail for an invalid APP_DEBUG value")
+	}
+}
+
+func TestSchemaMigration(t *testing.T) {
+	clearConfigEnv(t)
+
+	// Version 1 used server.listen_port; version 2 renamed it to server.port
+	path := writeConfigFile(t, "config.yaml", `
+schema_version: 1
+server:
+  listen_port: "9090"
+  host: "0.0.0.0"
+`)
+
+	loader := config.NewLoader()
+	loader.RegisterMigration(1, func(settings map[string]interface{}) map[string]interface{} {
+		if server, ok := settings["server"].(map[string]interface{}); ok {
+			if port, ok := server["listen_port"]; ok {
+				server["port"] = port
+				delete(server, "listen_port")
+			}
+		}
+		return settings
+	})
+
+	cfg, err := loader.LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.Server.Port != "9090" {
+		t.Errorf("Expected migrated server port 9090, got %s", cfg.Server.Port)
+	}
+	if cfg.SchemaVersion != 2 {
+		t.Errorf("Expected schema version 2, got %d", cfg.SchemaVersion)
+	}
+}
+
+func TestSchemaMigrationDropsLegacyKeys(t *testing.T) {
+	clearConfigEnv(t)
+
+	path := writeConfigFile(t, "config.yaml", `
+database:
+  host: "primary.internal"
+  max_conns: 20
+`)
+
+	loader := config.NewLoader()
+	loader.RegisterMigration(1, func(settings map[string]interface{}) map[string]interface{} {
+		if database, ok := settings["database"].(map[string]interface{}); ok {
+			database["write_host"] = database["host"]
+			delete(database, "host")
+		}
+		return settings
+	})
+
+	cfg, err := loader.LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.Database.DBWriteHost != "primary.internal" {
+		t.Errorf("Expected migrated write host primary.internal, got %q", cfg.Database.DBWriteHost)
+	}
+	if cfg.Database.Host != "localhost" {
+		t.Errorf("Expected the removed legacy host to fall back to localhost, got %q", cfg.Database.Host)
+	}
+	if cfg.Database.MaxConns != 20 {
+		t.Errorf("Expected untouched max conns 20, got %d", cfg.Database.MaxConns)
+	}
+	if source := loader.FieldSources()["database.host"]; source != config.SourceDefault {
+		t.Errorf("Expected database.host to be reported as a default, got %q", source)
+	}
+}
+
+func TestSchemaMigrationSkippedForCurrentVersion(t *testing.T) {
+	clearConfigEnv(t)
+
+	path := writeConfigFile(t, "config.yaml", `
+schema_version: 2
+server:
+  port: "8081"
+`)
+
+	loader := config.NewLoader()
+	loader.RegisterMigration(1, func(settings map[string]interface{}) map[string]interface{} {
+		t.Error("Migration from version 1 should not run for a version 2 file")
+		return settings
+	})
+
+	cfg, err := loader.LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.Server.Port != "8081" || cfg.SchemaVersion != 2 {
+		t.Errorf("Unexpected config: port=%s schema_version=%d", cfg.Server.Port, cfg.SchemaVersion)
+	}
+}
+
+func TestEnvVars(t *testing.T) {
+	vars := make(map[string]bool)
+	for _, name := range config.EnvVars() {
+		if vars[name] {
+			t.Errorf("Duplicate environment variable %s", name)
+		}
+		vars[name] = true
+	}
+
+	expected := []string{
+		"SERVER_PORT", "DB_HOST", "JWT_SECRET",
+		"DB_WRITE_HOST", "DB_WRITE_PORT", "DB_WRITE_USER", "DB_WRITE_PASSWORD", "DB_WRITE_NAME",
+		"DB_READ_HOST", "DB_READ_PORT", "DB_READ_USER", "DB_READ_PASSWORD", "DB_READ_NAME",
+		"DATABASE_CONFIG_TYPE",
+	}
+	for _, name := range expected {
+		if !vars[name] {
+			t.Errorf("Expected EnvVars() to include %s", name)
+		}
+	}
+}
+
+func TestExportEnvContract(t *testing.T) {
+	data, err := config.ExportEnvContract()
+	if err != nil {
+		t.Fatalf("ExportEnvContract failed: %v", err)
+	}
+
+	var specs []config.EnvVarSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		t.Fatalf("Contract is not valid JSON: %v", err)
+	}
+	if len(specs) != len(config.EnvVars()) {
+		t.Errorf("Expected %d entries, got %d", len(config.EnvVars()), len(specs))
+	}
+
+	byName := make(map[string]config.EnvVarSpec, len(specs))
+	for _, spec := range specs {
+		byName[spec.Name] = spec
+	}
+
+	want := map[string]config.EnvVarSpec{
+		"JWT_SECRET":       {Type: "string", Default: "your-secret-key", Fields: []string{"jwt.secret"}},
+		"JWT_EXPIRATION":   {Type: "duration", Default: "24h", Fields: []string{"jwt.expiration"}},
+		"DB_PORT":          {Type: "string", Default: "5432", Fields: []string{"database.port"}},
+		"DB_MAX_CONNS":     {Type: "integer", Default: "10", Fields: []string{"database.max_conns"}},
+		"APP_DEBUG":        {Type: "boolean", Default: "false", Fields: []string{"app.debug"}},
+		"DB_READ_REPLICAS": {Type: "list", Default: "", Fields: []string{"database.read_replicas"}},
+		"APP_ENVIRONMENT":  {Type: "string", Default: "development", Fields: []string{"database.environment", "app.environment"}},
+	}
+	for name, expected := range want {
+		got, ok := byName[name]
+		if !ok {
+			t.Errorf("Expected an entry for %s", name)
+			continue
+		}
+		if got.Description == "" {
+			t.Errorf("Expected %s to have a description", name)
+		}
+		expected.Name, expected.Description = name, got.Description
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("%s: expected %+v, got %+v", name, expected, got)
+		}
+	}
+
+	for _, spec := range specs {
+		if spec.Required && spec.Default != "" {
+			t.Errorf("%s has a default and must not be marked required", spec.Name)
+		}
+	}
+}
+
+func TestLoadFromEnvironmentDefaults(t *testing.T) {
+	clearConfigEnv(t)
+
+	cfg, err := config.NewLoader().LoadFromEnvironment()
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	if cfg.Server.Port != "8080" || cfg.Server.ReadTimeout != 30*time.Second {
+		t.Errorf("Unexpected server defaults: %+v", cfg.Server)
+	}
+	if cfg.Database.MaxConns != 10 || cfg.Database.DatabaseConfigType != "auto_detect" {
+		t.Errorf("Unexpected database defaults: %+v", cfg.Database)
+	}
+	if cfg.Email.Port != 587 || cfg.JWT.Expiration != 24*time.Hour {
+		t.Errorf("Unexpected email/jwt defaults: %+v %+v", cfg.Email, cfg.JWT)
+	}
+	if cfg.App.Environment != "development" || cfg.Database.Environment != "development" {
+		t.Errorf("Unexpected environment defaults: %s %s", cfg.App.Environment, cfg.Database.Environment)
+	}
+}
+
+func TestJWTExpirationDayUnits(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"1d", 24 * time.Hour},
+		{"7d", 168 * time.Hour},
+		{"30d", 720 * time.Hour},
+		{"12h", 12 * time.Hour},
+		{"1d12h", 36 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			setValidEnv(t)
+			t.Setenv("JWT_EXPIRATION", tt.value)
+
+			cfg, err := config.NewLoader().LoadFromEnvironment()
+			if err != nil {
+				t.Fatalf("Failed to load configuration: %v", err)
+			}
+			if cfg.JWT.Expiration != tt.expected {
+				t.Errorf("JWT_EXPIRATION=%s: expected %s, got %s", tt.value, tt.expected, cfg.JWT.Expiration)
+			}
+		})
+	}
+}
+
+func TestJWTExpirationDayUnitsFromFile(t *testing.T) {
+	clearConfigEnv(t)
+
+	path := writeConfigFile(t, "config.yaml", strings.Replace(validYAML, `expiration: "24h"`, `expiration: "7d"`, 1))
+
+	cfg, err := config.NewLoader().LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.JWT.Expiration != 168*time.Hour {
+		t.Errorf("Expected 168h, got %s", cfg.JWT.Expiration)
+	}
+	if err := config.NewValidator().Validate(cfg); err != nil {
+		t.Errorf("Validation should pass: %v", err)
+	}
+}
+
+func TestLoadFromGlob(t *testing.T) {
+	clearConfigEnv(t)
+
+	dir := filepath.Join(t.TempDir(), "conf.d")
+	if err := os.Mkdir(dir, 0700); err != nil {
+		t.Fatalf("Failed to create conf.d: %v", err)
+	}
+
+	fragments := map[string]string{
+		"00-base.yaml": validYAML,
+		"10-server.yaml": `
+server:
+  port: "9090"
+log:
+  level: "warn"
+`,
+		"20-overrides.yaml": `
+log:
+  level: "debug"
+`,
+	}
+	for name, content := range fragments {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	cfg, err := config.NewLoader().LoadFromGlob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to load from glob: %v", err)
+	}
+
+	if cfg.Server.Port != "9090" {
+		t.Errorf("Expected port from 10-server.yaml, got %s", cfg.Server.Port)
+	}
+	if cfg.Log.Level != "debug" {
+		t.Errorf("Expected log level from the last fragment, got %s", cfg.Log.Level)
+	}
+	if cfg.Server.Host != "0.0.0.0" || cfg.Database.Host != "localhost" {
+		t.Errorf("Expected untouched values from 00-base.yaml, got host=%s db=%s", cfg.Server.Host, cfg.Database.Host)
+	}
+}
+
+func TestLoadFromGlobNoMatches(t *testing.T) {
+	_, err := config.NewLoader().LoadFromGlob(filepath.Join(t.TempDir(), "*.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "no config files match") {
+		t.Errorf("Expected a clear no-match error, got %v", err)
+	}
+}
+
+func TestLoadEnvironmentFile(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("APP_ENVIRONMENT", "production")
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"default.yaml":    validYAML,
+		"production.yaml": strings.Replace(validYAML, `port: "8080"`, `port: "9443"`, 1),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	loader := config.NewLoader()
+	cfg, err := loader.LoadEnvironmentFile(dir)
+	if err != nil {
+		t.Fatalf("Failed to load environment file: %v", err)
+	}
+	if cfg.Server.Port != "9443" {
+		t.Errorf("Expected port from production.yaml, got %s", cfg.Server.Port)
+	}
+	if files := loader.LoadedFiles(); len(files) != 1 || filepath.Base(files[0]) != "production.yaml" {
+		t.Errorf("Expected production.yaml to be loaded, got %v", files)
+	}
+}
+
+func TestLoadEnvironmentFileFallsBackToDefault(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("APP_ENVIRONMENT", "staging")
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "default.yaml"), []byte(validYAML), 0600); err != nil {
+		t.Fatalf("Failed to write default.yaml: %v", err)
+	}
+
+	loader := config.NewLoader()
+	cfg, err := loader.LoadEnvironmentFile(dir)
+	if err != nil {
+		t.Fatalf("Failed to load environment file: %v", err)
+	}
+	if cfg.Server.Port != "8080" {
+		t.Errorf("Expected port from default.yaml, got %s", cfg.Server.Port)
+	}
+	if files := loader.LoadedFiles(); len(files) != 1 || filepath.Base(files[0]) != "default.yaml" {
+		t.Errorf("Expected default.yaml to be loaded, got %v", files)
+	}
+
+	if _, err := config.NewLoader().LoadEnvironmentFile(t.TempDir()); err == nil || !strings.Contains(err.Error(), "staging.yaml") {
+		t.Errorf("Expected an error naming the missing files, got %v", err)
+	}
+}
+
+func TestLoadFromStdin(t *testing.T) {
+	clearConfigEnv(t)
+
+	loader := config.NewLoader()
+	loader.SetStdin(strings.NewReader(validYAML))
+
+	cfg, err := loader.LoadFromStdin("yaml")
+	if err != nil {
+		t.Fatalf("Failed to load configuration from stdin: %v", err)
+	}
+	if cfg.App.Name != "Test Application" || cfg.Server.ReadTimeout != 30*time.Second {
+		t.Errorf("Unexpected parsed config: %+v", cfg)
+	}
+}
+
+func TestLoadFromBytes(t *testing.T) {
+	clearConfigEnv(t)
+
+	cfg, err := config.NewLoader().LoadFromBytes([]byte(validYAML), "yaml")
+	if err != nil {
+		t.Fatalf("Failed to load YAML bytes: %v", err)
+	}
+	if cfg.App.Name != "Test Application" || cfg.Server.ReadTimeout != 30*time.Second {
+		t.Errorf("Unexpected YAML config: %+v %+v", cfg.App, cfg.Server)
+	}
+
+	cfg, err = config.NewLoader().LoadFromBytes([]byte(`{"server": {"port": "7070", "read_timeout": "15s"}, "app": {"name": "JSON App"}}`), "json")
+	if err != nil {
+		t.Fatalf("Failed to load JSON bytes: %v", err)
+	}
+	if cfg.Server.Port != "7070" || cfg.Server.ReadTimeout != 15*time.Second || cfg.App.Name != "JSON App" {
+		t.Errorf("Unexpected JSON config: %+v %+v", cfg.Server, cfg.App)
+	}
+
+	if _, err := config.NewLoader().LoadFromBytes([]byte(`{"server": `), "json"); err == nil {
+		t.Error("Expected an error for malformed JSON")
+	}
+}
+
+func TestStdinStrategy(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("CONFIG_FORMAT", "json")
+
+	loader := config.NewLoader()
+	loader.SetStdin(strings.NewReader(`{"server": {"port": "7070", "host": "127.0.0.1"}}`))
+
+	cfg, err := loader.Load(config.StdinStrategy)
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.Server.Port != "7070" || cfg.Server.Host != "127.0.0.1" {
+		t.Errorf("Unexpected server config: %+v", cfg.Server)
+	}
+
+	// The explicit format must not leak into later file loads
+	path := writeConfigFile(t, "config.yaml", validYAML)
+	if _, err := loader.LoadFromFile(path); err != nil {
+		t.Errorf("Subsequent YAML file load failed: %v", err)
+	}
+}
+
+func TestIntEnvParsing(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int
+		valid    bool
+	}{
+		{"123", 123, true},
+		{" 42 ", 42, true},
+		{"123abc", 10, false},
+		{"-5", 10, false},
+		{"", 10, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			setValidEnv(t)
+			t.Setenv("DB_MAX_CONNS", tt.value)
+
+			cfg, err := config.NewLoader().LoadFromEnvironment()
+			if err != nil {
+				t.Fatalf("Non-strict load should not fail: %v", err)
+			}
+			if cfg.Database.MaxConns != tt.expected {
+				t.Errorf("DB_MAX_CONNS=%q: expected %d, got %d", tt.value, tt.expected, cfg.Database.MaxConns)
+			}
+
+			loader := config.NewLoader()
+			loader.SetStrict(true)
+			_, err = loader.LoadFromEnvironment()
+			if (err == nil) != tt.valid {
+				t.Errorf("DB_MAX_CONNS=%q: expected strict error=%t, got %v", tt.value, !tt.valid, err)
+			}
+		})
+	}
+}
+
+func TestRedisPoolEnv(t *testing.T) {
+	setValidEnv(t)
+
+	cfg, err := config.NewLoader().LoadFromEnvironment()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Redis.PoolSize != 10 || cfg.Redis.MinIdleConns != 0 {
+		t.Errorf("Expected default pool size 10 and min idle 0, got %d and %d", cfg.Redis.PoolSize, cfg.Redis.MinIdleConns)
+	}
+
+	t.Setenv("REDIS_POOL_SIZE", "50")
+	t.Setenv("REDIS_MIN_IDLE_CONNS", "5")
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if redisCfg := manager.GetRedisConfig(); redisCfg.PoolSize != 50 || redisCfg.MinIdleConns != 5 {
+		t.Errorf("Expected pool size 50 and min idle 5, got %d and %d", redisCfg.PoolSize, redisCfg.MinIdleConns)
+	}
+
+	t.Setenv("REDIS_MIN_IDLE_CONNS", "80")
+	if err := manager.Load(config.EnvironmentStrategy); err == nil || !strings.Contains(err.Error(), "must not exceed the pool size") {
+		t.Errorf("Expected min idle > pool size to fail validation, got %v", err)
+	}
+}
+
+func TestEnvIndirection(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("DB_PASSWORD", "")
+	t.Setenv("DB_PASSWORD_FROM", "SECRET_DB_PW")
+	t.Setenv("SECRET_DB_PW", "real-password")
+
+	loader := config.NewLoader()
+	loader.RequireEnv("DB_PASSWORD")
+	cfg, err := loader.LoadFromEnvironment()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Database.Password != "real-password" {
+		t.Errorf("Expected password from SECRET_DB_PW, got %q", cfg.Database.Password)
+	}
+	if got := loader.FieldSources()["database.password"]; got != config.SourceEnv {
+		t.Errorf("Expected database.password from env, got %q", got)
+	}
+
+	// A directly-set variable wins over its _FROM indirection
+	t.Setenv("DB_PASSWORD", "direct-password")
+	cfg, err = config.NewLoader().LoadFromEnvironment()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Database.Password != "direct-password" {
+		t.Errorf("Expected DB_PASSWORD to take precedence, got %q", cfg.Database.Password)
+	}
+}
+
+func TestEnvIndirectionHybrid(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("DB_PASSWORD_FROM", "SECRET_DB_PW")
+	t.Setenv("SECRET_DB_PW", "real-password")
+
+	cfg, err := config.NewLoader().Load(config.HybridStrategy)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.Database.Password != "real-password" {
+		t.Errorf("Expected password from SECRET_DB_PW, got %q", cfg.Database.Password)
+	}
+}
+
+func TestEnvIndirectionUnsetTarget(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("DB_PASSWORD", "")
+	t.Setenv("DB_PASSWORD_FROM", "SECRET_DB_PW")
+	t.Setenv("SECRET_DB_PW", "")
+
+	loader := config.NewLoader()
+	loader.SetStrict(true)
+	_, err := loader.LoadFromEnvironment()
+	if err == nil || !strings.Contains(err.Error(), "DB_PASSWORD_FROM names SECRET_DB_PW, which is not set") {
+		t.Errorf("Expected an error naming the unset target, got %v", err)
+	}
+}
+
+func TestDurationEnvParsingStrict(t *testing.T) {
+	for _, value := range []string{"30", "-5s", "10 seconds", "200000d"} {
+		setValidEnv(t)
+		t.Setenv("SERVER_READ_TIMEOUT", value)
+
+		loader := config.NewLoader()
+		loader.SetStrict(true)
+		if _, err := loader.LoadFromEnvironment(); err == nil {
+			t.Errorf("SERVER_READ_TIMEOUT=%q: expected a strict parse error", value)
+		}
+	}
+}
+
+func TestFileEnvExpansion(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("TEST_EXPAND_DB_PASSWORD", "s3cr3t")
+	t.Setenv("TEST_EXPAND_DB_HOST", "db.internal")
+	t.Setenv("TEST_EXPAND_TIMEOUT", "45s")
+
+	content := strings.NewReplacer(
+		`password: "password"`, `password: "${TEST_EXPAND_DB_PASSWORD}"`,
+		`host: "localhost"`, `host: "${TEST_EXPAND_DB_HOST}"`,
+		`read_timeout: "30s"`, `read_timeout: "${TEST_EXPAND_TIMEOUT}"`,
+		`issuer: "testapp"`, `issuer: "${TEST_EXPAND_UNSET_ISSUER}"`,
+	).Replace(validYAML)
+	path := writeConfigFile(t, "config.yaml", content)
+
+	loader := config.NewLoader()
+	cfg, err := loader.LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	if cfg.Database.Password != "s3cr3t" {
+		t.Errorf("Expected expanded password, got %q", cfg.Database.Password)
+	}
+	if cfg.Database.Host != "db.internal" {
+		t.Errorf("Expected expanded host, got %q", cfg.Database.Host)
+	}
+	if cfg.Server.ReadTimeout != 45*time.Second {
+		t.Errorf("Expected expanded read timeout 45s, got %s", cfg.Server.ReadTimeout)
+	}
+	if cfg.JWT.Issuer != "" {
+		t.Errorf("Expected unset variable to expand to empty, got %q", cfg.JWT.Issuer)
+	}
+
+	warnings := loader.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "TEST_EXPAND_UNSET_ISSUER") {
+		t.Errorf("Expected a warning about the unset variable, got %v", warnings)
+	}
+}
+
+func TestFileEnvExpansionKeepsDollarSigns(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("DEF", "from-environment")
+
+	content := strings.NewReplacer(
+		`password: "password"`, `password: "pa$word$$x"`,
+		`secret: "test-secret-that-is-long-enough-for-validation"`, `secret: "abc$DEF-jwt-secret-that-is-long-enough"`,
+		`issuer: "testapp"`, `issuer: "$${DEF}"`,
+	).Replace(validYAML)
+	path := writeConfigFile(t, "config.yaml", content)
+
+	loader := config.NewLoader()
+	cfg, err := loader.LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.Database.Password != "pa$word$x" {
+		t.Errorf("Expected the password's dollar signs to be kept, got %q", cfg.Database.Password)
+	}
+	if cfg.JWT.Secret != "abc$DEF-jwt-secret-that-is-long-enough" {
+		t.Errorf("Expected an unbraced $DEF to be kept, got %q", cfg.JWT.Secret)
+	}
+	if cfg.JWT.Issuer != "${DEF}" {
+		t.Errorf("Expected $$ to escape a reference, got %q", cfg.JWT.Issuer)
+	}
+	if warnings := loader.Warnings(); len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+}
+
+func TestFileEnvExpansionDisabled(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("TEST_EXPAND_DB_PASSWORD", "s3cr3t")
+
+	path := writeConfigFile(t, "config.yaml", strings.Replace(validYAML, `password: "password"`, `password: "${TEST_EXPAND_DB_PASSWORD}"`, 1))
+
+	loader := config.NewLoader()
+	loader.SetExpandEnv(false)
+	cfg, err := loader.LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.Database.Password != "${TEST_EXPAND_DB_PASSWORD}" {
+		t.Errorf("Expected the literal placeholder with expansion disabled, got %q", cfg.Database.Password)
+	}
+}
+
+func TestLoadTOMLDurations(t *testing.T) {
+	clearConfigEnv(t)
+
+	path := writeConfigFile(t, "config.toml", `
+[server]
+port = "8080"
+read_timeout = 30000000000
+write_timeout = "45s"
+idle_timeout = 6.0e10
+
+[jwt]
+expiration = "7d"
+`)
+	cfg, err := config.NewLoader().LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load TOML file: %v", err)
+	}
+
+	want := map[string][2]time.Duration{
+		"server.read_timeout":  {cfg.Server.ReadTimeout, 30 * time.Second},
+		"server.write_timeout": {cfg.Server.WriteTimeout, 45 * time.Second},
+		"server.idle_timeout":  {cfg.Server.IdleTimeout, time.Minute},
+		"jwt.expiration":       {cfg.JWT.Expiration, 7 * 24 * time.Hour},
+	}
+	for field, got := range want {
+		if got[0] != got[1] {
+			t.Errorf("Expected %s to be %s, got %s", field, got[1], got[0])
+		}
+	}
+
+	fractional := writeConfigFile(t, "fractional.toml", `
+[server]
+read_timeout = 1.5
+`)
+	if _, err := config.NewLoader().LoadFromFile(fractional); err == nil || !strings.Contains(err.Error(), "whole nanoseconds") {
+		t.Errorf("Expected a fractional numeric duration to be rejected, got %v", err)
+	}
+}
+
+func TestLoadFromFileWithFormat(t *testing.T) {
+	clearConfigEnv(t)
+
+	yamlPath := writeConfigFile(t, "config", validYAML)
+	cfg, err := config.NewLoader().LoadFromFileWithFormat(yamlPath, "yaml")
+	if err != nil {
+		t.Fatalf("Failed to load extensionless YAML: %v", err)
+	}
+	if cfg.App.Name != "Test Application" {
+		t.Errorf("Unexpected app name %q", cfg.App.Name)
+	}
+
+	tomlPath := writeConfigFile(t, "config.conf", `
+[server]
+port = "9191"
+host = "127.0.0.1"
+read_timeout = "15s"
+
+[app]
+name = "TOML App"
+`)
+	cfg, err = config.NewLoader().LoadFromFileWithFormat(tomlPath, "toml")
+	if err != nil {
+		t.Fatalf("Failed to load .conf file as TOML: %v", err)
+	}
+	if cfg.Server.Port != "9191" || cfg.Server.ReadTimeout != 15*time.Second || cfg.App.Name != "TOML App" {
+		t.Errorf("Unexpected TOML config: %+v %+v", cfg.Server, cfg.App)
+	}
+
+	if _, err := config.NewLoader().LoadFromFile(yamlPath); err == nil {
+		t.Error("Loading an extensionless file without a format should fail")
+	}
+}
+
+func TestLoadFromFileWithBOM(t *testing.T) {
+	clearConfigEnv(t)
+
+	path := writeConfigFile(t, "config.yaml", "\xEF\xBB\xBF"+validYAML)
+	cfg, err := config.NewLoader().LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load YAML with a BOM: %v", err)
+	}
+	if cfg.App.Name != "Test Application" || cfg.Server.Port != "8080" {
+		t.Errorf("Unexpected parsed config: %+v %+v", cfg.App, cfg.Server)
+	}
+
+	cfg, err = config.NewLoader().LoadFromReader(strings.NewReader("\xEF\xBB\xBF"+validYAML), "yaml")
+	if err != nil {
+		t.Fatalf("Failed to read YAML with a BOM: %v", err)
+	}
+	if cfg.App.Name != "Test Application" {
+		t.Errorf("Unexpected app name %q", cfg.App.Name)
+	}
+}
+
+func TestLoadFromFileNonUTF8(t *testing.T) {
+	clearConfigEnv(t)
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"UTF-16", "\xFF\xFEs\x00e\x00r\x00v\x00e\x00r\x00:\x00", "UTF-16"},
+		{"Latin-1", "app:\n  name: \"Caf\xE9\"\n", "not valid UTF-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, "config.yaml", tt.content)
+			_, err := config.NewLoader().LoadFromFile(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error mentioning %q, got: %v", tt.want, err)
+			}
+
+			_, err = config.NewLoader().LoadFromReader(strings.NewReader(tt.content), "yaml")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected a reader error mentioning %q, got: %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestLoadFromFileFormatsRoundTrip(t *testing.T) {
+	clearConfigEnv(t)
+
+	files := map[string]string{
+		"config.yaml": `
+server:
+  port: "9090"
+  host: "127.0.0.1"
+  read_timeout: "15s"
+  write_timeout: "0.5s"
+  idle_timeout: "2m"
+database:
+  host: "db.internal"
+  port: "5432"
+  max_conns: 20
+email:
+  allowed_from_domains: ["example.com", "mail.example.com"]
+jwt:
+  secret: "a-very-long-secret-key-that-is-at-least-32-characters"
+  expiration: "7d"
+app:
+  name: "Format Test"
+  debug: true
+`,
+		"config.json": `{
+  "server": {"port": "9090", "host": "127.0.0.1", "read_timeout": "15s", "write_timeout": "0.5s", "idle_timeout": "2m"},
+  "database": {"host": "db.internal", "port": "5432", "max_conns": 20},
+  "email": {"allowed_from_domains": ["example.com", "mail.example.com"]},
+  "jwt": {"secret": "a-very-long-secret-key-that-is-at-least-32-characters", "expiration": "7d"},
+  "app": {"name": "Format Test", "debug": true}
+}`,
+		"config.toml": `
+[server]
+port = "9090"
+host = "127.0.0.1"
+read_timeout = "15s"
+write_timeout = "0.5s"
+idle_timeout = "2m"
+
+[database]
+host = "db.internal"
+port = "5432"
+max_conns = 20
+
+[email]
+allowed_from_domains = ["example.com", "mail.example.com"]
+
+[jwt]
+secret = "a-very-long-secret-key-that-is-at-least-32-characters"
+expiration = "7d"
+
+[app]
+name = "Format Test"
+debug = true
+`,
+	}
+	files["config.yml"] = files["config.yaml"]
+
+	want, err := config.NewLoader().LoadFromFile(writeConfigFile(t, "config.yaml", files["config.yaml"]))
+	if err != nil {
+		t.Fatalf("Failed to load YAML: %v", err)
+	}
+	if want.Server.WriteTimeout != 500*time.Millisecond || want.Database.MaxConns != 20 || !want.App.Debug {
+		t.Fatalf("Unexpected YAML config: %+v %+v %+v", want.Server, want.Database, want.App)
+	}
+
+	for name, content := range files {
+		got, err := config.NewLoader().LoadFromFile(writeConfigFile(t, name, content))
+		if err != nil {
+			t.Errorf("%s: failed to load: %v", name, err)
+			continue
+		}
+		if !want.Equal(got) {
+			t.Errorf("%s: differs from the YAML config: %v", name, config.Diff(want, got))
+		}
+	}
+
+	// Forcing the format loads the same content from an extensionless file
+	got, err := config.NewLoader().LoadFromFileWithFormat(writeConfigFile(t, "config", files["config.toml"]), "TOML")
+	if err != nil {
+		t.Fatalf("Failed to load extensionless TOML: %v", err)
+	}
+	if !want.Equal(got) {
+		t.Errorf("Extensionless TOML differs from the YAML config: %v", config.Diff(want, got))
+	}
+}
+
+func TestLoadFromFileUnsupportedFormat(t *testing.T) {
+	clearConfigEnv(t)
+
+	path := writeConfigFile(t, "config.ini", "[server]\nport = 8080\n")
+	_, err := config.NewLoader().LoadFromFile(path)
+	if err == nil || !strings.Contains(err.Error(), `unsupported config format ".ini"`) {
+		t.Errorf("Expected an unsupported format error for .ini, got: %v", err)
+	}
+
+	_, err = config.NewLoader().LoadFromFileWithFormat(path, "ini")
+	if err == nil || !strings.Contains(err.Error(), `unsupported config format "ini"`) {
+		t.Errorf("Expected an unsupported format error for a forced ini format, got: %v", err)
+	}
+
+	_, err = config.NewLoader().LoadFromFile(writeConfigFile(t, "config", validYAML))
+	if err == nil || !strings.Contains(err.Error(), "missing file extension") {
+		t.Errorf("Expected a missing extension error, got: %v", err)
+	}
+
+	_, err = config.NewLoader().LoadFromReader(strings.NewReader("[server]\nport = 8080\n"), "ini")
+	if err == nil || !strings.Contains(err.Error(), `unsupported config format "ini"`) {
+		t.Errorf("Expected LoadFromReader to reject ini, got: %v", err)
+	}
+	if _, err := config.NewLoader().LoadFromReader(strings.NewReader(validYAML), "YAML"); err != nil {
+		t.Errorf("Expected LoadFromReader to accept an upper-case format, got: %v", err)
+	}
+
+	fsys := fstest.MapFS{"config.ini": &fstest.MapFile{Data: []byte("[server]\nport = 8080\n")}}
+	_, err = config.NewLoader().LoadFromFS(fsys, "config.ini")
+	if err == nil || !strings.Contains(err.Error(), `unsupported config format "ini"`) {
+		t.Errorf("Expected LoadFromFS to reject ini, got: %v", err)
+	}
+
+	loader := config.NewLoader()
+	if err := loader.MapSection("redis", config.SectionSource{Path: path}); err == nil || !strings.Contains(err.Error(), `unsupported config format ".ini"`) {
+		t.Errorf("Expected MapSection to reject an .ini file, got: %v", err)
+	}
+	if err := loader.MapSection("redis", config.SectionSource{Data: []byte("port = 6380"), Format: "properties"}); err == nil || !strings.Contains(err.Error(), `unsupported config format "properties"`) {
+		t.Errorf("Expected MapSection to reject a properties format, got: %v", err)
+	}
+}
+
+func TestPartialSectionDefaults(t *testing.T) {
+	clearConfigEnv(t)
+
+	path := writeConfigFile(t, "config.yaml", `
+database:
+  host: "db.internal"
+`)
+
+	cfg, err := config.NewLoader().LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	db := cfg.Database
+	if db.Host != "db.internal" {
+		t.Errorf("Expected configured host to be kept, got %s", db.Host)
+	}
+	if db.Port != "5432" || db.User != "postgres" || db.SSLMode != "disable" || db.MaxConns != 10 {
+		t.Errorf("Expected defaults for missing database fields, got %+v", db)
+	}
+
+	// Sections absent from the file are not defaulted
+	if cfg.Redis.Host != "" || cfg.Server.Port != "" {
+		t.Errorf("Expected absent sections to stay empty, got redis=%+v server=%+v", cfg.Redis, cfg.Server)
+	}
+}
+
+func TestLoadFromFS(t *testing.T) {
+	clearConfigEnv(t)
+
+	fsys := fstest.MapFS{
+		"defaults/config.yaml": &fstest.MapFile{Data: []byte(validYAML)},
+		"defaults/config":      &fstest.MapFile{Data: []byte(validYAML)},
+	}
+
+	cfg, err := config.NewLoader().LoadFromFS(fsys, "defaults/config.yaml")
+	if err != nil {
+		t.Fatalf("Failed to load config from fs.FS: %v", err)
+	}
+	if cfg.App.Name != "Test Application" {
+		t.Errorf("Expected app name from embedded file, got %q", cfg.App.Name)
+	}
+	if cfg.JWT.Expiration != 24*time.Hour {
+		t.Errorf("Expected JWT expiration 24h, got %s", cfg.JWT.Expiration)
+	}
+
+	if _, err := config.NewLoader().LoadFromFS(fsys, "defaults/missing.yaml"); err == nil {
+		t.Error("Expected error for missing file")
+	}
+	if _, err := config.NewLoader().LoadFromFS(fsys, "defaults/config"); err == nil {
+		t.Error("Expected error for file without extension")
+	}
+}
+
+func TestFilePermissionCheck(t *testing.T) {
+	clearConfigEnv(t)
+
+	tests := []struct {
+		name        string
+		mode        os.FileMode
+		wantWarning bool
+	}{
+		{"private", 0600, false},
+		{"world readable", 0644, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, "config.yaml", validYAML)
+			if err := os.Chmod(path, tt.mode); err != nil {
+				t.Fatalf("Failed to chmod config file: %v", err)
+			}
+
+			loader := config.NewLoader()
+			loader.SetPermissionCheck(true)
+			if _, err := loader.LoadFromFile(path); err != nil {
+				t.Fatalf("Permission check must only warn outside strict mode: %v", err)
+			}
+			if got := len(loader.Warnings()) > 0; got != tt.wantWarning {
+				t.Errorf("Expected warning=%t, got %v", tt.wantWarning, loader.Warnings())
+			}
+
+			strict := config.NewLoader()
+			strict.SetPermissionCheck(true)
+			strict.SetStrict(true)
+			if _, err := strict.LoadFromFile(path); (err != nil) != tt.wantWarning {
+				t.Errorf("Strict mode: expected error=%t, got %v", tt.wantWarning, err)
+			}
+		})
+	}
+}
+
+func TestFilePermissionCheckWithoutSecrets(t *testing.T) {
+	clearConfigEnv(t)
+
+	path := writeConfigFile(t, "config.yaml", "server:\n  port: \"8080\"\n")
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatalf("Failed to chmod config file: %v", err)
+	}
+
+	loader := config.NewLoader()
+	loader.SetPermissionCheck(true)
+	if _, err := loader.LoadFromFile(path); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if warnings := loader.Warnings(); len(warnings) != 0 {
+		t.Errorf("Files without secrets should not be flagged, got %v", warnings)
+	}
+}
+
+func TestLoadInto(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("SERVER_PORT", "9090")
+	t.Setenv("REDIS_HOST", "redis.internal")
+
+	var custom struct {
+		Server struct {
+			Port        int           `mapstructure:"port"`
+			ReadTimeout time.Duration `mapstructure:"read_timeout"`
+		} `mapstructure:"server"`
+		Redis struct {
+			Host string `mapstructure:"host"`
+		} `mapstructure:"redis"`
+	}
+
+	if err := config.NewLoader().LoadInto(config.EnvironmentStrategy, &custom); err != nil {
+		t.Fatalf("Failed to load into custom struct: %v", err)
+	}
+
+	if custom.Server.Port != 9090 {
+		t.Errorf("Expected server port 9090, got %d", custom.Server.Port)
+	}
+	if custom.Server.ReadTimeout != 30*time.Second {
+		t.Errorf("Expected read timeout 30s, got %s", custom.Server.ReadTimeout)
+	}
+	if custom.Redis.Host != "redis.internal" {
+		t.Errorf("Expected redis host redis.internal, got %q", custom.Redis.Host)
+	}
+}
+
+func TestLoadSection(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("CONFIG_PATH", writeConfigFile(t, "config.yaml", strings.Replace(validYAML, `host: "localhost"
+  port: "6379"`, `host: "redis.internal"
+  port: "6380"`, 1)))
+
+	var redisCfg config.RedisConfig
+	if err := config.NewLoader().LoadSection(config.FileStrategy, "redis", &redisCfg); err != nil {
+		t.Fatalf("LoadSection failed: %v", err)
+	}
+	if redisCfg.Host != "redis.internal" || redisCfg.Port != "6380" {
+		t.Errorf("Expected redis.internal:6380, got %s:%s", redisCfg.Host, redisCfg.Port)
+	}
+
+	var partial struct {
+		Port int `mapstructure:"port"`
+	}
+	if err := config.NewLoader().LoadSection(config.FileStrategy, "redis", &partial); err != nil {
+		t.Fatalf("LoadSection into a custom struct failed: %v", err)
+	}
+	if partial.Port != 6380 {
+		t.Errorf("Expected port 6380, got %d", partial.Port)
+	}
+
+	if err := config.NewLoader().LoadSection(config.FileStrategy, "cache", &redisCfg); err == nil {
+		t.Error("Expected an error for an unknown section")
+	}
+}
+
+func TestMapSection(t *testing.T) {
+	setValidEnv(t)
+
+	loader := config.NewLoader()
+	err := loader.MapSection("database", config.SectionSource{
+		Data:   []byte(`{"services": {"billing": {"database": {"host": "billing-db.internal", "port": 6432, "dbname": "billing", "user": "billing", "password": "secret"}}}}`),
+		Format: "json",
+		Prefix: "services.billing.database",
+	})
+	if err != nil {
+		t.Fatalf("MapSection(database) failed: %v", err)
+	}
+	err = loader.MapSection("redis", config.SectionSource{
+		Data:   []byte("host: cache.internal\nport: \"6380\"\npool_size: 25\n"),
+		Format: "yaml",
+	})
+	if err != nil {
+		t.Fatalf("MapSection(redis) failed: %v", err)
+	}
+
+	cfg, err := loader.Load(config.EnvironmentStrategy)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Database.Host != "billing-db.internal" || cfg.Database.Port != "6432" || cfg.Database.DBName != "billing" {
+		t.Errorf("Database section not read from its source: %+v", cfg.Database)
+	}
+	if cfg.Redis.Host != "cache.internal" || cfg.Redis.Port != "6380" || cfg.Redis.PoolSize != 25 {
+		t.Errorf("Redis section not read from its source: %+v", cfg.Redis)
+	}
+	if cfg.Server.Port != "8080" || cfg.App.Name == "" {
+		t.Errorf("Unmapped sections must come from the strategy: %+v %+v", cfg.Server, cfg.App)
+	}
+	if cfg.Database.SSLMode == "" {
+		t.Error("Keys a section source omits must keep the strategy's values")
+	}
+	if err := config.NewValidator().Validate(cfg); err != nil {
+		t.Errorf("Assembled configuration should be valid: %v", err)
+	}
+
+	sources := loader.FieldSources()
+	if sources["database.host"] != config.SourceMemory || sources["redis.pool_size"] != config.SourceMemory {
+		t.Errorf("Expected mapped fields to be reported as %q, got %q and %q", config.SourceMemory, sources["database.host"], sources["redis.pool_size"])
+	}
+}
+
+func TestMapSectionErrors(t *testing.T) {
+	setValidEnv(t)
+
+	loader := config.NewLoader()
+	if err := loader.MapSection("cache", config.SectionSource{Data: []byte("{}"), Format: "json"}); err == nil {
+		t.Error("Expected an error for an unknown section")
+	}
+	if err := loader.MapSection("redis", config.SectionSource{Data: []byte("{}")}); err == nil {
+		t.Error("Expected an error for in-memory data without a format")
+	}
+	if err := loader.MapSection("redis", config.SectionSource{}); err == nil {
+		t.Error("Expected an error for a source without a path or data")
+	}
+
+	if err := loader.MapSection("redis", config.SectionSource{Data: []byte(`{"cache": {}}`), Format: "json", Prefix: "redis"}); err != nil {
+		t.Fatalf("MapSection failed: %v", err)
+	}
+	if _, err := loader.Load(config.EnvironmentStrategy); err == nil || !strings.Contains(err.Error(), "section redis") {
+		t.Errorf("Expected an error for a missing prefix, got: %v", err)
+	}
+}
+
+func TestLoadIntoRequiresPointer(t *testing.T) {
+	setValidEnv(t)
+
+	var custom struct{}
+	if err := config.NewLoader().LoadInto(config.EnvironmentStrategy, custom); err == nil {
+		t.Error("Expected error when decoding into a non-pointer")
+	}
+}
+
+func TestLoadFromFileDirectory(t *testing.T) {
+	clearConfigEnv(t)
+	dir := t.TempDir()
+
+	_, err := config.NewLoader().LoadFromFile(dir)
+	if err == nil {
+		t.Fatal("Expected error when loading a directory")
+	}
+	if !strings.Contains(err.Error(), "config path is a directory, expected a file") {
+		t.Errorf("Expected clear directory error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "LoadFromGlob") {
+		t.Errorf("Expected error to suggest LoadFromGlob, got %v", err)
+	}
+
+	t.Setenv("CONFIG_PATH", dir)
+	if _, err := config.NewLoader().Load(config.FileStrategy); err == nil || !strings.Contains(err.Error(), "is a directory") {
+		t.Errorf("Expected directory error from FileStrategy, got %v", err)
+	}
+}
+
+func TestEnvKeyReplacer(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("EXTENSIONS_FEATURE_FLAGS_CHANNEL", "beta")
+
+	path := writeConfigFile(t, "config.yaml", validYAML+`
+extensions:
+  feature-flags:
+    channel: stable
+`)
+
+	var flags struct {
+		Channel string `json:"channel"`
+	}
+
+	// The default replacer leaves the hyphen in place, so the variable does not match
+	cfg, err := config.NewLoader().LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if err := json.Unmarshal(cfg.Extensions["feature-flags"], &flags); err != nil {
+		t.Fatalf("Failed to decode extension: %v", err)
+	}
+	if flags.Channel != "stable" {
+		t.Errorf("Default replacer should not map hyphenated keys, got channel %q", flags.Channel)
+	}
+
+	loader := config.NewLoader()
+	loader.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	cfg, err = loader.LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if err := json.Unmarshal(cfg.Extensions["feature-flags"], &flags); err != nil {
+		t.Fatalf("Failed to decode extension: %v", err)
+	}
+	if flags.Channel != "beta" {
+		t.Errorf("Expected EXTENSIONS_FEATURE_FLAGS_CHANNEL to override the hyphenated key, got %q", flags.Channel)
+	}
+}
+
+func TestLoadedFiles(t *testing.T) {
+	clearConfigEnv(t)
+
+	dir := t.TempDir()
+	base := filepath.Join(dir, "00-base.yaml")
+	overrides := filepath.Join(dir, "10-overrides.yaml")
+	if err := os.WriteFile(base, []byte(validYAML), 0600); err != nil {
+		t.Fatalf("Failed to write base config: %v", err)
+	}
+	if err := os.WriteFile(overrides, []byte("log:\n  level: \"debug\"\n"), 0600); err != nil {
+		t.Fatalf("Failed to write override config: %v", err)
+	}
+
+	loader := config.NewLoader()
+	if _, err := loader.LoadFromFile(base); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if got := loader.LoadedFiles(); len(got) != 1 || got[0] != base {
+		t.Errorf("Expected [%s], got %v", base, got)
+	}
+
+	if _, err := loader.LoadFromGlob(filepath.Join(dir, "*.yaml")); err != nil {
+		t.Fatalf("Failed to load layered config: %v", err)
+	}
+	if got := loader.LoadedFiles(); len(got) != 2 || got[0] != base || got[1] != overrides {
+		t.Errorf("Expected [%s %s] in merge order, got %v", base, overrides, got)
+	}
+
+	t.Setenv("CONFIG_PATH", base)
+	if _, err := loader.Load(config.HybridStrategy); err != nil {
+		t.Fatalf("Failed hybrid load: %v", err)
+	}
+	if got := loader.LoadedFiles(); len(got) != 1 || got[0] != base {
+		t.Errorf("Expected hybrid load to report [%s], got %v", base, got)
+	}
+
+	setValidEnv(t)
+	if _, err := loader.LoadFromEnvironment(); err != nil {
+		t.Fatalf("Failed to load from environment: %v", err)
+	}
+	if got := loader.LoadedFiles(); len(got) != 0 {
+		t.Errorf("Expected no files for an environment load, got %v", got)
+	}
+}
+
+func TestTemplating(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("APP_ENVIRONMENT", "staging")
+	t.Setenv("APP_NAME", "{{ .App.Environment }}-service")
+	t.Setenv("LOG_OUTPUT_PATH", "/var/log/{{ .App.Name }}.log")
+
+	loader := config.NewLoader()
+	loader.SetTemplating(true)
+	cfg, err := loader.Load(config.EnvironmentStrategy)
+	if err != nil {
+		t.Fatalf("Failed to load templated config: %v", err)
+	}
+
+	if cfg.App.Name != "staging-service" {
+		t.Errorf("Expected app name staging-service, got %q", cfg.App.Name)
+	}
+	if cfg.Log.OutputPath != "/var/log/staging-service.log" {
+		t.Errorf("Expected chained template to resolve, got %q", cfg.Log.OutputPath)
+	}
+
+	// Templates are left as-is unless templating is enabled
+	cfg, err = config.NewLoader().Load(config.EnvironmentStrategy)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.App.Name != "{{ .App.Environment }}-service" {
+		t.Errorf("Expected template to be left unresolved, got %q", cfg.App.Name)
+	}
+}
+
+func TestTemplatingReferenceForms(t *testing.T) {
+	tests := map[string]string{
+		"root variable": "{{ $.App.Environment }}-service",
+		"chain":         "{{ (.App).Environment }}-service",
+		"with":          "{{ with .App }}{{ .Environment }}{{ end }}-service",
+	}
+
+	for name, template := range tests {
+		t.Run(name, func(t *testing.T) {
+			setValidEnv(t)
+			t.Setenv("APP_ENVIRONMENT", "staging")
+			t.Setenv("APP_VERSION", template)
+			t.Setenv("APP_NAME", "{{ .App.Version }}")
+
+			loader := config.NewLoader()
+			loader.SetTemplating(true)
+			cfg, err := loader.Load(config.EnvironmentStrategy)
+			if err != nil {
+				t.Fatalf("Failed to load templated config: %v", err)
+			}
+			if cfg.App.Version != "staging-service" || cfg.App.Name != "staging-service" {
+				t.Errorf("Expected staging-service, got version %q and name %q", cfg.App.Version, cfg.App.Name)
+			}
+		})
+	}
+}
+
+func TestTemplatingUnsupportedReferences(t *testing.T) {
+	for _, template := range []string{
+		"{{ $c := . }}{{ $c.App.Name }}",
+		"{{ range .Database.ReadReplicas }}{{ .Host }}{{ end }}",
+		`{{ define "x" }}{{ .App.Name }}{{ end }}{{ template "x" . }}`,
+	} {
+		setValidEnv(t)
+		t.Setenv("APP_VERSION", template)
+
+		loader := config.NewLoader()
+		loader.SetTemplating(true)
+		if _, err := loader.Load(config.EnvironmentStrategy); err == nil || !strings.Contains(err.Error(), "invalid template in app.version") {
+			t.Errorf("Expected %q to be rejected, got %v", template, err)
+		}
+	}
+}
+
+func TestTemplatingSkipsSecrets(t *testing.T) {
+	setValidEnv(t)
+	secret := "{{ .App.Name }}-jwt-secret-that-is-long-enough"
+	t.Setenv("JWT_SECRET", secret)
+	t.Setenv("APP_VERSION", "secret://version")
+
+	loader := config.NewLoader()
+	loader.SetTemplating(true)
+	loader.SetSecretProvider(fakeSecretProvider{"version": "{{ .App.Name }}"})
+	cfg, err := loader.Load(config.EnvironmentStrategy)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.JWT.Secret != secret {
+		t.Errorf("Expected the secret field to be left as-is, got %q", cfg.JWT.Secret)
+	}
+	if cfg.App.Version != "{{ .App.Name }}" {
+		t.Errorf("Expected the provider's value to be left as-is, got %q", cfg.App.Version)
+	}
+}
+
+func TestTemplatingRejectsSecretReferences(t *testing.T) {
+	tests := map[string]string{
+		"secret field":        "{{ .JWT.Secret }}",
+		"root variable":       "{{ $.Database.Password }}",
+		"section":             "{{ .Database }}",
+		"whole config":        "{{ . }}",
+		"root":                "{{ $ }}",
+		"with body":           "{{ with .Redis }}{{ .Password }}{{ end }}",
+		"with secret":         "{{ with .JWT.Secret }}set{{ end }}",
+		"range over secrets":  "{{ range .JWT.PreviousSecrets }}{{ . }}{{ end }}",
+		"provider secret":     "{{ .App.Version }}",
+		"condition on secret": "{{ if .Email.Password }}auth{{ end }}",
+	}
+
+	for name, template := range tests {
+		t.Run(name, func(t *testing.T) {
+			setValidEnv(t)
+			t.Setenv("APP_VERSION", "secret://version")
+			t.Setenv("LOG_OUTPUT_PATH", template)
+
+			loader := config.NewLoader()
+			loader.SetTemplating(true)
+			loader.SetSecretProvider(fakeSecretProvider{"version": "1.0.0"})
+			_, err := loader.Load(config.EnvironmentStrategy)
+			if err == nil || !strings.Contains(err.Error(), "must not reference the secret field") {
+				t.Errorf("Expected %q to be rejected, got %v", template, err)
+			}
+		})
+	}
+
+	// Sections holding secrets may still scope a with action
+	setValidEnv(t)
+	t.Setenv("LOG_OUTPUT_PATH", "/var/log/{{ with .Database }}{{ .Host }}{{ end }}.log")
+	loader := config.NewLoader()
+	loader.SetTemplating(true)
+	cfg, err := loader.Load(config.EnvironmentStrategy)
+	if err != nil {
+		t.Fatalf("Failed to load templated config: %v", err)
+	}
+	if cfg.Log.OutputPath != "/var/log/localhost.log" {
+		t.Errorf("Expected the with action to resolve, got %q", cfg.Log.OutputPath)
+	}
+}
+
+func TestTemplatingCycles(t *testing.T) {
+	tests := map[string]map[string]string{
+		"self reference": {"APP_NAME": "{{ .App.Name }}-service"},
+		"root variable":  {"APP_NAME": "{{ $.App.Name }}-service"},
+		"chain":          {"APP_NAME": "{{ (.App).Name }}-service"},
+		"with":           {"APP_NAME": "{{ with .App }}{{ .Version }}{{ end }}", "APP_VERSION": "{{ .App.Name }}"},
+		"mutual reference": {
+			"APP_NAME":    "{{ .App.Version }}",
+			"APP_VERSION": "{{ .App.Name }}",
+		},
+	}
+
+	for name, env := range tests {
+		t.Run(name, func(t *testing.T) {
+			setValidEnv(t)
+			for key, value := range env {
+				t.Setenv(key, value)
+			}
+
+			loader := config.NewLoader()
+			loader.SetTemplating(true)
+			_, err := loader.Load(config.EnvironmentStrategy)
+			if err == nil || !strings.Contains(err.Error(), "cycle") {
+				t.Errorf("Expected template cycle error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRequireEnv(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("JWT_SECRET", "")
+
+	loader := config.NewLoader()
+	loader.RequireEnv("JWT_SECRET", "DB_PASSWORD")
+
+	_, err := loader.LoadFromEnvironment()
+	if err == nil {
+		t.Fatal("Expected error for unset JWT_SECRET")
+	}
+	if want := "required environment variables not set: JWT_SECRET"; err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+
+	t.Setenv("JWT_SECRET", "a-secret-that-is-definitely-long-enough")
+	if _, err := loader.LoadFromEnvironment(); err != nil {
+		t.Errorf("Expected load to succeed once JWT_SECRET is set: %v", err)
+	}
+
+	// Without RequireEnv the default applies
+	t.Setenv("JWT_SECRET", "")
+	cfg, err := config.NewLoader().LoadFromEnvironment()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if cfg.JWT.Secret != "your-secret-key" {
+		t.Errorf("Expected default JWT secret, got %q", cfg.JWT.Secret)
+	}
+}
+
+func TestFileBoolTokens(t *testing.T) {
+	clearConfigEnv(t)
+
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{`"yes"`, true},
+		{`1`, true},
+		{`"on"`, true},
+		{`"no"`, false},
+		{`0`, false},
+		{`true`, true},
+	}
+
+	for _, tt := range tests {
+		path := writeConfigFile(t, "config.yaml", validYAML+"  debug: "+tt.value+"\n")
+		cfg, err := config.NewLoader().LoadFromFile(path)
+		if err != nil {
+			t.Errorf("debug: %s: failed to load config: %v", tt.value, err)
+			continue
+		}
+		if cfg.App.Debug != tt.want {
+			t.Errorf("debug: %s: expected %t, got %t", tt.value, tt.want, cfg.App.Debug)
+		}
+	}
+
+	path := writeConfigFile(t, "config.yaml", validYAML+"  debug: \"maybe\"\n")
+	if _, err := config.NewLoader().LoadFromFile(path); err == nil {
+		t.Error("Expected error for an unrecognized boolean token")
+	}
+}
+
+func TestEnvSnapshotReplay(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("SERVER_PORT", "9191")
+	t.Setenv("SERVER_TIMEOUT_UPLOAD", "5m")
+
+	loader := config.NewLoader()
+	original, err := loader.LoadFromEnvironment()
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	snap := loader.CaptureEnvSnapshot()
+	if snap["SERVER_PORT"] != "9191" || snap["SERVER_TIMEOUT_UPLOAD"] != "5m" {
+		t.Errorf("Expected the snapshot to record the variables read, got %v", snap)
+	}
+	if _, ok := snap["PATH"]; ok {
+		t.Error("Snapshot should only hold variables the loader read")
+	}
+
+	clearConfigEnv(t)
+	os.Unsetenv("SERVER_TIMEOUT_UPLOAD")
+
+	replayed, err := config.NewLoader().LoadFromSnapshot(snap)
+	if err != nil {
+		t.Fatalf("Failed to load from snapshot: %v", err)
+	}
+	if !reflect.DeepEqual(original, replayed) {
+		t.Errorf("Replayed configuration differs: %v", config.Diff(original, replayed))
+	}
+
+	// The process environment is used again once the replay is over
+	after, err := loader.LoadFromEnvironment()
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if after.Server.Port != "8080" {
+		t.Errorf("Expected the default port after clearing the environment, got %s", after.Server.Port)
+	}
+}
+
+func TestEnvSnapshotReplayFileStrategy(t *testing.T) {
+	clearConfigEnv(t)
+	path := writeConfigFile(t, "config.yaml", strings.Replace(validYAML, `issuer: "testapp"`, `issuer: "${SNAPSHOT_ISSUER}"`, 1))
+	t.Setenv("CONFIG_PATH", path)
+	t.Setenv("SERVER_PORT", "9292")
+	t.Setenv("SNAPSHOT_ISSUER", "snapshot-issuer")
+
+	loader := config.NewLoader()
+	original, err := loader.Load(config.FileStrategy)
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	snap := loader.CaptureEnvSnapshot()
+	for name, want := range map[string]string{"CONFIG_PATH": path, "SERVER_PORT": "9292", "SNAPSHOT_ISSUER": "snapshot-issuer"} {
+		if snap[name] != want {
+			t.Errorf("Expected the snapshot to record %s=%s, got %v", name, want, snap)
+		}
+	}
+
+	clearConfigEnv(t)
+	os.Unsetenv("SNAPSHOT_ISSUER")
+
+	replayed, err := config.NewLoader().LoadWithSnapshot(config.FileStrategy, snap)
+	if err != nil {
+		t.Fatalf("Failed to load from snapshot: %v", err)
+	}
+	if !reflect.DeepEqual(original, replayed) {
+		t.Errorf("Replayed configuration differs: %v", config.Diff(original, replayed))
+	}
+	if replayed.Server.Port != "9292" || replayed.JWT.Issuer != "snapshot-issuer" {
+		t.Errorf("Expected the snapshot's override and reference, got port %s, issuer %s", replayed.Server.Port, replayed.JWT.Issuer)
+	}
+}
+
+func TestLoadAndValidate(t *testing.T) {
+	setValidEnv(t)
+
+	cfg, err := config.LoadAndValidate(config.EnvironmentStrategy)
+	if err != nil {
+		t.Fatalf("Failed to load and validate configuration: %v", err)
+	}
+	if cfg.Server.Port != "8080" {
+		t.Errorf("Expected port 8080, got %s", cfg.Server.Port)
+	}
+}
+
+func TestLoadAndValidateInvalid(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("JWT_SECRET", "short")
+
+	cfg, err := config.LoadAndValidate(config.EnvironmentStrategy)
+	if cfg != nil {
+		t.Error("Expected no configuration when validation fails")
+	}
+	var validationErr *config.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a *config.ValidationError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "JWT secret must be at least 32 characters long") {
+		t.Errorf("Expected the JWT secret violation, got %v", err)
+	}
+}
+
+func TestConfigPathPrecedence(t *testing.T) {
+	clearConfigEnv(t)
+
+	flagPath := writeConfigFile(t, "flag.yaml", strings.Replace(validYAML, `port: "8080"`, `port: "7070"`, 1))
+	envPath := writeConfigFile(t, "env.yaml", strings.Replace(validYAML, `port: "8080"`, `port: "9090"`, 1))
+
+	loader := config.NewLoader()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	loader.BindFlags(flags)
+
+	// Default
+	if got := loader.ConfigPath(); got != "config.yaml" {
+		t.Errorf("Expected the default config.yaml, got %s", got)
+	}
+
+	// CONFIG_PATH overrides the default
+	t.Setenv("CONFIG_PATH", envPath)
+	cfg, err := loader.Load(config.FileStrategy)
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.Server.Port != "9090" {
+		t.Errorf("Expected the file named by CONFIG_PATH, got port %s", cfg.Server.Port)
+	}
+
+	// --config overrides CONFIG_PATH
+	if err := flags.Parse([]string{"--config", flagPath}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+	cfg, err = loader.Load(config.FileStrategy)
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.Server.Port != "7070" {
+		t.Errorf("Expected the file named by --config, got port %s", cfg.Server.Port)
+	}
+	if got := loader.ConfigPath(); got != flagPath {
+		t.Errorf("Expected ConfigPath to report the flag, got %s", got)
+	}
+}
@@ -0,0 +1,1460 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sublimeai21/config"
+)
+
+func TestAddWatcherFor(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	dbWatcher := newChannelWatcher()
+	manager.AddWatcherFor([]string{"database"}, dbWatcher)
+
+	// A log-level-only change must not reach the database watcher
+	os.Setenv("LOG_LEVEL", "debug")
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Failed to reload configuration: %v", err)
+	}
+	if c := dbWatcher.waitForChange(100 * time.Millisecond); c != nil {
+		t.Error("Database watcher should not be notified for a log level change")
+	}
+
+	// A database change must reach it
+	os.Setenv("DB_HOST", "db.example.com")
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Failed to reload configuration: %v", err)
+	}
+	c := dbWatcher.waitForChange(time.Second)
+	if c == nil {
+		t.Fatal("Database watcher was not notified of a database change")
+	}
+	if c.Database.Host != "db.example.com" {
+		t.Errorf("Expected database host db.example.com, got %s", c.Database.Host)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	oldConfig := validConfig()
+	newConfig := validConfig()
+	newConfig.Log.Level = "debug"
+
+	changes := config.Diff(oldConfig, newConfig)
+	if len(changes) != 1 {
+		t.Fatalf("Expected 1 change, got %d: %v", len(changes), changes)
+	}
+	if changes[0].Field != "log.level" {
+		t.Errorf("Expected change to log.level, got %s", changes[0].Field)
+	}
+	if changes[0].OldValue != "info" || changes[0].NewValue != "debug" {
+		t.Errorf("Unexpected change values: %v -> %v", changes[0].OldValue, changes[0].NewValue)
+	}
+}
+
+func TestGetOperationTimeout(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("SERVER_TIMEOUT_UPLOAD", "5m")
+	t.Setenv("SERVER_TIMEOUT_REPORT", "90s")
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	if timeout, ok := manager.GetOperationTimeout("upload"); !ok || timeout != 5*time.Minute {
+		t.Errorf("Expected upload timeout 5m, got %s (found=%t)", timeout, ok)
+	}
+	if timeout, ok := manager.GetOperationTimeout("REPORT"); !ok || timeout != 90*time.Second {
+		t.Errorf("Expected report timeout 90s, got %s (found=%t)", timeout, ok)
+	}
+	if _, ok := manager.GetOperationTimeout("missing"); ok {
+		t.Error("Expected no timeout for an unconfigured operation")
+	}
+
+	// The global timeouts are unaffected
+	if manager.GetServerConfig().ReadTimeout != 30*time.Second {
+		t.Errorf("Expected default read timeout, got %s", manager.GetServerConfig().ReadTimeout)
+	}
+}
+
+func TestFailedReloadKeepsPreviousConfig(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	before := manager.GetConfig()
+	sourcesBefore := manager.FieldSources()
+
+	watcher := newChannelWatcher()
+	manager.AddWatcher(watcher)
+
+	// Break validation and reload
+	os.Setenv("SERVER_PORT", "9090")
+	os.Setenv("JWT_SECRET", "short")
+	if err := manager.Reload(); err == nil {
+		t.Fatal("Reload should fail validation")
+	}
+
+	if manager.GetConfig() != before {
+		t.Error("A failed reload must not replace the configuration")
+	}
+	if manager.GetServerConfig().Port != "8080" {
+		t.Errorf("Expected previous port 8080, got %s", manager.GetServerConfig().Port)
+	}
+	if !manager.IsLoaded() {
+		t.Error("IsLoaded() must remain true after a failed reload")
+	}
+	if sources := manager.FieldSources(); sources["server.port"] != sourcesBefore["server.port"] {
+		t.Errorf("Field sources changed after a failed reload: %v", sources["server.port"])
+	}
+	if c := watcher.waitForChange(100 * time.Millisecond); c != nil {
+		t.Error("Watchers must not be notified of a failed reload")
+	}
+}
+
+func TestFailedHybridReloadKeepsPreviousConfig(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	if err := manager.Load(config.HybridStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	before := manager.GetConfig()
+
+	os.Setenv("APP_ENVIRONMENT", "nowhere")
+	if err := manager.Load(config.HybridStrategy); err == nil {
+		t.Fatal("Load should fail validation")
+	}
+	if manager.GetConfig() != before {
+		t.Error("A failed hybrid load must not replace the configuration")
+	}
+}
+
+func TestUnmarshalExtension(t *testing.T) {
+	clearConfigEnv(t)
+
+	path := writeConfigFile(t, "config.yaml", validYAML+`
+extensions:
+  tracing:
+    endpoint: "http://otel-collector:4318"
+    sample_rate: 0.25
+    enabled: true
+`)
+	t.Setenv("CONFIG_PATH", path)
+
+	manager := config.NewManager()
+	if err := manager.Load(config.FileStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	var tracing struct {
+		Endpoint   string  `json:"endpoint"`
+		SampleRate float64 `json:"sample_rate"`
+		Enabled    bool    `json:"enabled"`
+	}
+	if err := manager.UnmarshalExtension("tracing", &tracing); err != nil {
+		t.Fatalf("UnmarshalExtension failed: %v", err)
+	}
+	if tracing.Endpoint != "http://otel-collector:4318" || tracing.SampleRate != 0.25 || !tracing.Enabled {
+		t.Errorf("Unexpected tracing extension: %+v", tracing)
+	}
+
+	if err := manager.UnmarshalExtension("metrics", &tracing); err == nil {
+		t.Error("Expected an error for a missing extension")
+	}
+}
+
+func TestValidateBindable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	setValidEnv(t)
+	t.Setenv("SERVER_HOST", "127.0.0.1")
+	t.Setenv("SERVER_PORT", port)
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	if err := manager.ValidateBindable(); err == nil {
+		t.Error("ValidateBindable should fail while the address is in use")
+	}
+
+	listener.Close()
+	if err := manager.ValidateBindable(); err != nil {
+		t.Errorf("ValidateBindable should pass once the address is free: %v", err)
+	}
+
+	if err := config.NewManager().ValidateBindable(); err == nil {
+		t.Error("ValidateBindable should fail when no configuration is loaded")
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	changes, unsubscribe := manager.Subscribe()
+
+	os.Setenv("SERVER_PORT", "9090")
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Failed to reload configuration: %v", err)
+	}
+
+	select {
+	case c := <-changes:
+		if c.Server.Port != "9090" {
+			t.Errorf("Expected new port 9090, got %s", c.Server.Port)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Subscriber did not receive the change")
+	}
+
+	unsubscribe()
+	unsubscribe() // idempotent
+
+	if _, ok := <-changes; ok {
+		t.Error("Expected the channel to be closed after unsubscribing")
+	}
+
+	// Reloading after unsubscribing must not panic
+	os.Setenv("SERVER_PORT", "9191")
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Failed to reload configuration: %v", err)
+	}
+}
+
+func TestSubscribeDropsOldest(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	changes, unsubscribe := manager.Subscribe()
+	defer unsubscribe()
+
+	// Overflow the buffer without reading
+	for i := 0; i < 20; i++ {
+		os.Setenv("SERVER_PORT", strconv.Itoa(9000+i))
+		if err := manager.Reload(); err != nil {
+			t.Fatalf("Failed to reload configuration: %v", err)
+		}
+	}
+
+	var last *config.Config
+	for len(changes) > 0 {
+		last = <-changes
+	}
+	if last == nil || last.Server.Port != "9019" {
+		t.Errorf("Expected the newest change to be retained, got %v", last)
+	}
+}
+
+func TestLoadAndReloadTimes(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	if !manager.LastLoadTime().IsZero() || !manager.LastReloadTime().IsZero() {
+		t.Fatal("Expected zero timestamps before the first load")
+	}
+
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	firstLoad := manager.LastLoadTime()
+	if firstLoad.IsZero() {
+		t.Fatal("Expected LastLoadTime to be set after loading")
+	}
+	if !manager.LastReloadTime().IsZero() {
+		t.Error("Expected LastReloadTime to stay zero until a reload")
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Failed to reload configuration: %v", err)
+	}
+	if !manager.LastLoadTime().After(firstLoad) {
+		t.Error("Expected LastLoadTime to advance after a reload")
+	}
+	if !manager.LastReloadTime().Equal(manager.LastLoadTime()) {
+		t.Error("Expected LastReloadTime to match the reload's load time")
+	}
+
+	// A failed reload does not advance the timestamps
+	reloaded := manager.LastReloadTime()
+	os.Setenv("JWT_SECRET", "short")
+	if err := manager.Reload(); err == nil {
+		t.Fatal("Expected reload to fail validation")
+	}
+	if !manager.LastReloadTime().Equal(reloaded) {
+		t.Error("A failed reload must not advance LastReloadTime")
+	}
+}
+
+func TestConcurrentLoadAndReloadTimes(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(reload bool) {
+			defer wg.Done()
+			if reload {
+				manager.Reload()
+			} else {
+				manager.Load(config.EnvironmentStrategy)
+			}
+		}(i%2 == 0)
+	}
+	wg.Wait()
+
+	if manager.LastReloadTime().After(manager.LastLoadTime()) {
+		t.Error("LastReloadTime must not be later than LastLoadTime")
+	}
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Failed to reload configuration: %v", err)
+	}
+	if !manager.LastReloadTime().Equal(manager.LastLoadTime()) {
+		t.Error("Expected LastReloadTime to match the reload's load time")
+	}
+}
+
+func TestUpdateJWTSecret(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	oldSecret := manager.GetJWTConfig().Secret
+	before := manager.GetConfig()
+
+	watcher := newChannelWatcher()
+	manager.AddWatcher(watcher)
+
+	newSecret := "rotated-secret-that-is-long-enough-for-validation"
+	if err := manager.UpdateJWTSecret(newSecret); err != nil {
+		t.Fatalf("UpdateJWTSecret failed: %v", err)
+	}
+
+	jwt := manager.GetJWTConfig()
+	if jwt.Secret != newSecret {
+		t.Errorf("Expected new secret, got %q", jwt.Secret)
+	}
+	if len(jwt.PreviousSecrets) != 1 || jwt.PreviousSecrets[0] != oldSecret {
+		t.Errorf("Expected old secret retained for verification, got %v", jwt.PreviousSecrets)
+	}
+	if before.JWT.Secret != oldSecret {
+		t.Error("Rotation must not mutate previously-returned configs")
+	}
+
+	c := watcher.waitForChange(time.Second)
+	if c == nil {
+		t.Fatal("Watcher was not notified of the rotation")
+	}
+	for _, change := range config.Diff(before, c) {
+		if !strings.HasPrefix(change.Field, "jwt.") {
+			t.Errorf("Expected only JWT changes, got %s", change.Field)
+		}
+	}
+
+	sources := manager.FieldSources()
+	if sources["jwt.secret"] != config.SourceOverride || sources["jwt.previous_secrets"] != config.SourceOverride {
+		t.Errorf("Expected the rotated fields to be reported as overridden, got %s and %s", sources["jwt.secret"], sources["jwt.previous_secrets"])
+	}
+}
+
+func TestUpdateJWTSecretBoundsPreviousSecrets(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	secrets := make([]string, 0)
+	for i := 0; i < 6; i++ {
+		secret := fmt.Sprintf("rotated-secret-%d-that-is-long-enough-for-validation", i)
+		if err := manager.UpdateJWTSecret(secret); err != nil {
+			t.Fatalf("UpdateJWTSecret failed: %v", err)
+		}
+		secrets = append(secrets, secret)
+	}
+
+	want := []string{secrets[4], secrets[3], secrets[2]}
+	if got := manager.GetJWTConfig().PreviousSecrets; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the 3 most recent previous secrets %v, got %v", want, got)
+	}
+}
+
+func TestUpdateJWTSecretRejectsInvalid(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	if err := manager.UpdateJWTSecret("rotated-secret-that-is-long-enough-for-validation"); err == nil {
+		t.Error("Expected an error when no configuration is loaded")
+	}
+
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	oldSecret := manager.GetJWTConfig().Secret
+
+	if err := manager.UpdateJWTSecret("short"); err == nil {
+		t.Error("Expected a too-short secret to be rejected")
+	}
+	if err := manager.UpdateJWTSecret(oldSecret); err == nil {
+		t.Error("Expected the current secret to be rejected")
+	}
+	if manager.GetJWTConfig().Secret != oldSecret || len(manager.GetJWTConfig().PreviousSecrets) != 0 {
+		t.Error("A rejected rotation must leave the configuration unchanged")
+	}
+}
+
+// mutexConfigHolder reproduces the previous RWMutex-guarded read path for comparison
+type mutexConfigHolder struct {
+	mutex  sync.RWMutex
+	config *config.Config
+}
+
+func (h *mutexConfigHolder) GetServerConfig() config.ServerConfig {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	if h.config == nil {
+		return config.ServerConfig{}
+	}
+	return h.config.Server
+}
+
+func BenchmarkGetServerConfigMutex(b *testing.B) {
+	holder := &mutexConfigHolder{config: validConfig()}
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = holder.GetServerConfig()
+		}
+	})
+}
+
+func BenchmarkGetServerConfigAtomic(b *testing.B) {
+	setValidEnv(b)
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		b.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = manager.GetServerConfig()
+		}
+	})
+}
+
+func TestConcurrentGetConfigDuringReload(t *testing.T) {
+	setValidEnv(t)
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if c := manager.GetConfig(); c == nil || c.Server.Port == "" {
+					t.Error("GetConfig returned an incomplete configuration during reload")
+					return
+				}
+				_ = manager.GetServerConfig()
+			}
+		}()
+	}
+
+	for i := 0; i < 20; i++ {
+		if err := manager.Reload(); err != nil {
+			t.Errorf("Failed to reload configuration: %v", err)
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestLoadConfig(t *testing.T) {
+	manager := config.NewManager()
+
+	cfg := validConfig()
+	cfg.Server.Port = "9090"
+	cfg.Redis.Host = "redis.internal"
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+
+	if !manager.IsLoaded() {
+		t.Fatal("Expected configuration to be loaded")
+	}
+	if got := manager.GetServerConfig().Port; got != "9090" {
+		t.Errorf("Expected server port 9090, got %s", got)
+	}
+	if got := manager.GetRedisConfig().Host; got != "redis.internal" {
+		t.Errorf("Expected redis host redis.internal, got %s", got)
+	}
+	if got := manager.FieldSources()["server.port"]; got != config.SourceMemory {
+		t.Errorf("Expected server.port source %q, got %q", config.SourceMemory, got)
+	}
+
+	// The manager keeps its own copy
+	cfg.Server.Port = "1234"
+	if got := manager.GetServerConfig().Port; got != "9090" {
+		t.Errorf("Caller mutation leaked into the manager: port %s", got)
+	}
+}
+
+func TestLoadConfigCopiesMapsAndSlices(t *testing.T) {
+	cfg := validConfig()
+	cfg.Server.Timeouts = map[string]time.Duration{"upload": 5 * time.Minute}
+	cfg.Extensions = map[string]json.RawMessage{"billing": json.RawMessage(`{"plan":"pro"}`)}
+	cfg.Database.ReadReplicas = []string{"replica-1.internal:5432"}
+	cfg.JWT.PreviousSecrets = []string{"previous-secret-that-is-at-least-32-chars"}
+	cfg.Email = config.EmailConfig{Host: "smtp.example.com", Port: 587, Username: "user", From: "noreply@myapp.com", AllowedFromDomains: []string{"myapp.com"}}
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+
+	cfg.Server.Timeouts["upload"] = time.Second
+	cfg.Extensions["billing"][2] = 'X'
+	cfg.Database.ReadReplicas[0] = "changed:5432"
+	cfg.JWT.PreviousSecrets[0] = "changed"
+	cfg.Email.AllowedFromDomains[0] = "changed.com"
+
+	current := manager.GetConfig()
+	if got := current.Server.Timeouts["upload"]; got != 5*time.Minute {
+		t.Errorf("Caller mutation leaked into timeouts: %s", got)
+	}
+	if got := string(current.Extensions["billing"]); got != `{"plan":"pro"}` {
+		t.Errorf("Caller mutation leaked into extensions: %s", got)
+	}
+	if got := current.Database.ReadReplicas[0]; got != "replica-1.internal:5432" {
+		t.Errorf("Caller mutation leaked into read replicas: %s", got)
+	}
+	if got := current.JWT.PreviousSecrets[0]; got == "changed" {
+		t.Error("Caller mutation leaked into previous JWT secrets")
+	}
+	if got := current.Email.AllowedFromDomains[0]; got != "myapp.com" {
+		t.Errorf("Caller mutation leaked into allowed from domains: %s", got)
+	}
+}
+
+func TestLoadConfigNotifiesWatchers(t *testing.T) {
+	manager := config.NewManager()
+	if err := manager.LoadConfig(validConfig()); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+
+	watcher := newChannelWatcher()
+	manager.AddWatcher(watcher)
+
+	updated := validConfig()
+	updated.Log.Level = "debug"
+	if err := manager.LoadConfig(updated); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+
+	c := watcher.waitForChange(time.Second)
+	if c == nil {
+		t.Fatal("Watcher was not notified")
+	}
+	if c.Log.Level != "debug" {
+		t.Errorf("Expected log level debug, got %s", c.Log.Level)
+	}
+}
+
+func TestLoadConfigRejectsInvalid(t *testing.T) {
+	manager := config.NewManager()
+	if err := manager.LoadConfig(validConfig()); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+
+	invalid := validConfig()
+	invalid.JWT.Secret = "short"
+	if err := manager.LoadConfig(invalid); err == nil {
+		t.Fatal("Expected validation error")
+	}
+	if got := manager.GetJWTConfig().Secret; got != validConfig().JWT.Secret {
+		t.Error("Invalid configuration replaced the previous one")
+	}
+
+	if err := manager.LoadConfig(nil); err == nil {
+		t.Error("Expected error for nil configuration")
+	}
+}
+
+func TestManagerEqual(t *testing.T) {
+	setValidEnv(t)
+
+	first := config.NewManager()
+	second := config.NewManager()
+	if !first.Equal(second) {
+		t.Error("Two unloaded managers should be equal")
+	}
+
+	if err := first.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if first.Equal(second) || second.Equal(first) {
+		t.Error("A loaded and an unloaded manager should not be equal")
+	}
+	if first.Equal(nil) {
+		t.Error("A loaded manager should not equal a nil manager")
+	}
+
+	if err := second.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if !first.Equal(second) {
+		t.Errorf("Managers loaded from identical environments should be equal: %v", config.Diff(first.GetConfig(), second.GetConfig()))
+	}
+
+	t.Setenv("SERVER_PORT", "9090")
+	third := config.NewManager()
+	if err := third.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if first.Equal(third) {
+		t.Error("Managers loaded from different environments should not be equal")
+	}
+}
+
+func TestSetLogLevel(t *testing.T) {
+	manager := config.NewManager()
+	if err := manager.LoadConfig(validConfig()); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+
+	watcher := newChannelWatcher()
+	manager.AddWatcherFor([]string{"log"}, watcher)
+
+	if err := manager.SetLogLevel("DEBUG"); err != nil {
+		t.Fatalf("Failed to set log level: %v", err)
+	}
+
+	c := watcher.waitForChange(time.Second)
+	if c == nil {
+		t.Fatal("Log watcher was not notified of the level change")
+	}
+	if c.Log.Level != "debug" {
+		t.Errorf("Expected watcher to receive log level debug, got %s", c.Log.Level)
+	}
+	if got := manager.GetLogConfig().Level; got != "debug" {
+		t.Errorf("Expected GetLogConfig().Level debug, got %s", got)
+	}
+	if got := manager.FieldSources()["log.level"]; got != config.SourceOverride {
+		t.Errorf("Expected log.level to be reported as overridden, got %s", got)
+	}
+
+	if err := manager.SetLogLevel("verbose"); err == nil {
+		t.Error("Expected error for invalid log level")
+	}
+	if got := manager.GetLogConfig().Level; got != "debug" {
+		t.Errorf("Invalid log level replaced the current one: %s", got)
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	manager := config.NewManager()
+	if manager.IsValid() {
+		t.Error("IsValid should be false when no configuration is loaded")
+	}
+
+	if err := manager.LoadConfig(validConfig()); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+	if !manager.IsValid() {
+		t.Error("IsValid should be true for a valid configuration")
+	}
+
+	// Callers can mutate the returned configuration in place, bypassing validation
+	manager.GetConfig().JWT.Secret = "short"
+	if manager.IsValid() {
+		t.Error("IsValid should be false for an invalid configuration")
+	}
+}
+
+func TestMaintenanceMode(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if manager.IsMaintenanceMode() {
+		t.Error("Maintenance mode should default to false")
+	}
+
+	watcher := newChannelWatcher()
+	manager.AddWatcherFor([]string{"app.maintenance_mode"}, watcher)
+
+	t.Setenv("APP_MAINTENANCE_MODE", "on")
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Failed to reload configuration: %v", err)
+	}
+
+	c := watcher.waitForChange(time.Second)
+	if c == nil {
+		t.Fatal("Watcher was not notified when maintenance mode was toggled")
+	}
+	if !c.App.MaintenanceMode {
+		t.Error("Watcher should see maintenance mode enabled")
+	}
+	if !manager.IsMaintenanceMode() {
+		t.Error("Expected IsMaintenanceMode() to be true after reload")
+	}
+}
+
+func TestRecommendedMaxConns(t *testing.T) {
+	tests := []struct {
+		environment string
+		readWrite   bool
+		want        int
+	}{
+		{"test", false, 2},
+		{"development", false, 5},
+		{"staging", false, 20},
+		{"production", false, 50},
+		{"production", true, 25},
+		{"test", true, 1},
+	}
+
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.App.Environment = tt.environment
+		if tt.environment == "production" {
+			cfg.Email = config.EmailConfig{Host: "smtp.example.com", Port: 587, Username: "user", From: "noreply@example.com"}
+			cfg.Server.TLSCertFile, cfg.Server.TLSKeyFile = "/etc/app/tls.crt", "/etc/app/tls.key"
+		}
+		if tt.readWrite {
+			cfg.Database = config.DatabaseConfig{
+				DatabaseConfigType: "read_write",
+				DBWriteHost:        "write.internal", DBWritePort: "5432", DBWriteUser: "app", DBWriteName: "app",
+				DBReadHost: "read.internal", DBReadPort: "5432", DBReadUser: "app", DBReadName: "app",
+				SSLMode: "disable", MaxConns: 10,
+			}
+		}
+
+		manager := config.NewManager()
+		if err := manager.LoadConfig(cfg); err != nil {
+			t.Fatalf("%s: failed to install configuration: %v", tt.environment, err)
+		}
+		if got := manager.RecommendedMaxConns(); got != tt.want {
+			t.Errorf("%s (read/write=%t): expected %d, got %d", tt.environment, tt.readWrite, tt.want, got)
+		}
+	}
+}
+
+func TestSQLPoolConfig(t *testing.T) {
+	cfg := validConfig()
+	cfg.Database.MaxConns = 40
+	cfg.Database.MaxIdleConns = 8
+	cfg.Database.ConnMaxLifetime = time.Hour
+	cfg.Database.ConnMaxIdleTime = 10 * time.Minute
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+
+	want := config.SQLPoolConfig{
+		MaxOpenConns:    40,
+		MaxIdleConns:    8,
+		ConnMaxLifetime: time.Hour,
+		ConnMaxIdleTime: 10 * time.Minute,
+	}
+	if got := manager.SQLPoolConfig(); got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestSQLPoolConfigDefaults(t *testing.T) {
+	manager := config.NewManager()
+	if err := manager.LoadConfig(validConfig()); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+
+	want := config.SQLPoolConfig{
+		MaxOpenConns:    10,
+		MaxIdleConns:    2,
+		ConnMaxLifetime: 30 * time.Minute,
+		ConnMaxIdleTime: 5 * time.Minute,
+	}
+	if got := manager.SQLPoolConfig(); got != want {
+		t.Errorf("Expected defaults %+v, got %+v", want, got)
+	}
+}
+
+func TestSQLPoolConfigCapsIdleConns(t *testing.T) {
+	cfg := validConfig()
+	cfg.Database.MaxConns = 4
+	cfg.Database.MaxIdleConns = 16
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+
+	if got := manager.SQLPoolConfig().MaxIdleConns; got != 4 {
+		t.Errorf("Expected MaxIdleConns to be capped at MaxOpenConns (4), got %d", got)
+	}
+}
+
+func TestSQLPoolConfigNoIdleConns(t *testing.T) {
+	cfg := validConfig()
+	cfg.Database.MaxIdleConns = -1
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+
+	if got := manager.SQLPoolConfig().MaxIdleConns; got != 0 {
+		t.Errorf("Expected a negative MaxIdleConns to disable idle connections (0), got %d", got)
+	}
+
+	// The sentinel can be set from the environment too
+	setValidEnv(t)
+	t.Setenv("DB_MAX_IDLE_CONNS", "-1")
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if got := manager.SQLPoolConfig().MaxIdleConns; got != 0 {
+		t.Errorf("Expected DB_MAX_IDLE_CONNS=-1 to disable idle connections (0), got %d", got)
+	}
+}
+
+// errorWatcher is a ConfigWatcherWithError returning a fixed error
+type errorWatcher struct {
+	err   error
+	calls int
+}
+
+func (w *errorWatcher) OnConfigChanged(oldConfig, newConfig *config.Config) error {
+	w.calls++
+	return w.err
+}
+
+func TestReloadReturnsWatcherErrors(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	manager.SetSynchronousNotify(true)
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	errPool := errors.New("cannot reopen database pool")
+	failing := &errorWatcher{err: errPool}
+	healthy := &errorWatcher{}
+	plain := newChannelWatcher()
+	manager.AddWatcherWithError(failing)
+	manager.AddWatcherWithError(healthy)
+	manager.AddWatcher(plain)
+
+	os.Setenv("SERVER_PORT", "9090")
+	err := manager.Reload()
+	if err == nil {
+		t.Fatal("Expected Reload to report the failing watcher")
+	}
+	var watcherErr *config.WatcherError
+	if !errors.As(err, &watcherErr) || len(watcherErr.Errors) != 1 {
+		t.Fatalf("Expected a *config.WatcherError with one error, got %v", err)
+	}
+	if !errors.Is(err, errPool) {
+		t.Errorf("Expected the watcher's error to be wrapped, got %v", err)
+	}
+
+	// The configuration is still installed and every watcher was notified
+	if port := manager.GetServerConfig().Port; port != "9090" {
+		t.Errorf("Expected reloaded port 9090, got %s", port)
+	}
+	if manager.LastReloadTime().IsZero() {
+		t.Error("Expected the reload to be recorded despite the watcher error")
+	}
+	if failing.calls != 1 || healthy.calls != 1 {
+		t.Errorf("Expected each error watcher to run once, got %d and %d", failing.calls, healthy.calls)
+	}
+	if len(plain.changes) != 1 {
+		t.Error("Expected plain watcher to be notified before Reload returned")
+	}
+
+	manager.RemoveWatcherWithError(failing)
+	os.Setenv("SERVER_PORT", "9091")
+	if err := manager.Reload(); err != nil {
+		t.Errorf("Expected Reload to succeed once the failing watcher is removed, got %v", err)
+	}
+}
+
+func TestWatcherErrorsIgnoredWhenAsynchronous(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	manager.AddWatcherWithError(&errorWatcher{err: errors.New("boom")})
+
+	os.Setenv("SERVER_PORT", "9090")
+	if err := manager.Reload(); err != nil {
+		t.Errorf("Expected asynchronous Reload to ignore watcher errors, got %v", err)
+	}
+}
+
+func TestReadWriteDatabaseHelpers(t *testing.T) {
+	readWrite := func(cfg *config.Config) {
+		cfg.Database.DBWriteHost, cfg.Database.DBWritePort = "write.internal", "5432"
+		cfg.Database.DBWriteUser, cfg.Database.DBWriteName = "writer", "app"
+		cfg.Database.DBReadHost, cfg.Database.DBReadPort = "read.internal", "5432"
+		cfg.Database.DBReadUser, cfg.Database.DBReadName = "reader", "app"
+	}
+
+	tests := []struct {
+		name       string
+		modify     func(cfg *config.Config)
+		wantType   string
+		writeParts []string
+		readParts  []string
+	}{
+		{"legacy", func(cfg *config.Config) {}, "legacy", []string{"localhost", "postgres"}, []string{"localhost", "postgres"}},
+		{"explicit read/write", func(cfg *config.Config) {
+			readWrite(cfg)
+			cfg.Database.DatabaseConfigType = "read_write"
+		}, "read_write", []string{"write.internal", "writer"}, []string{"read.internal", "reader"}},
+		{"auto-detected read/write", func(cfg *config.Config) {
+			readWrite(cfg)
+			cfg.Database.DatabaseConfigType = "auto_detect"
+		}, "read_write", []string{"write.internal", "writer"}, []string{"read.internal", "reader"}},
+		{"explicit legacy with read/write hosts", func(cfg *config.Config) {
+			readWrite(cfg)
+			cfg.Database.DatabaseConfigType = "legacy"
+		}, "legacy", []string{"localhost", "postgres"}, []string{"localhost", "postgres"}},
+		{"read/write falling back to legacy fields", func(cfg *config.Config) {
+			readWrite(cfg)
+			cfg.Database.DatabaseConfigType = "read_write"
+			cfg.Database.DBWritePassword = "write-secret"
+		}, "read_write", []string{"write.internal", "password=write-secret"}, []string{"read.internal", "password=password"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			manager := config.NewManager()
+			if err := manager.LoadConfig(cfg); err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+
+			if got := manager.GetResolvedDatabaseConfigType(); got != tt.wantType {
+				t.Errorf("Expected resolved config type %q, got %q", tt.wantType, got)
+			}
+			if got := manager.GetDatabaseConfigType(); got != cfg.Database.DatabaseConfigType {
+				t.Errorf("Expected raw config type %q, got %q", cfg.Database.DatabaseConfigType, got)
+			}
+			if got := manager.IsReadWriteDatabase(); got != (tt.wantType == "read_write") {
+				t.Errorf("Expected IsReadWriteDatabase=%t", !got)
+			}
+			for _, part := range tt.writeParts {
+				if dsn := manager.GetWriteDatabaseDSN(); !strings.Contains(dsn, part) {
+					t.Errorf("Expected write DSN to contain %q, got %q", part, dsn)
+				}
+			}
+			for _, part := range tt.readParts {
+				if dsn := manager.GetReadDatabaseDSN(); !strings.Contains(dsn, part) {
+					t.Errorf("Expected read DSN to contain %q, got %q", part, dsn)
+				}
+			}
+		})
+	}
+
+	if got := config.NewManager().GetResolvedDatabaseConfigType(); got != "" {
+		t.Errorf("Expected an empty config type on an unloaded manager, got %q", got)
+	}
+}
+
+func TestAddressHelpersUnloaded(t *testing.T) {
+	manager := config.NewManager()
+
+	helpers := map[string]func() string{
+		"GetDatabaseDSN":      manager.GetDatabaseDSN,
+		"GetWriteDatabaseDSN": manager.GetWriteDatabaseDSN,
+		"GetReadDatabaseDSN":  manager.GetReadDatabaseDSN,
+		"GetRedisAddr":        manager.GetRedisAddr,
+		"GetServerAddr":       manager.GetServerAddr,
+	}
+	for name, helper := range helpers {
+		if got := helper(); got != "" {
+			t.Errorf("Expected %s to be empty on an unloaded manager, got %q", name, got)
+		}
+	}
+
+	if err := manager.LoadConfig(validConfig()); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+	if got := manager.GetServerAddr(); got != "0.0.0.0:8080" {
+		t.Errorf("Expected server address once loaded, got %q", got)
+	}
+}
+
+func TestRedisURLResolution(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		addr     string
+		password string
+		db       int
+	}{
+		{"no url", "", "localhost:6379", "fieldpass", 0},
+		{"full url", "redis://:urlpass@cache.internal:6380/3", "cache.internal:6380", "urlpass", 3},
+		{"default port", "rediss://cache.internal", "cache.internal:6379", "fieldpass", 0},
+		{"ipv6 host", "redis://[::1]:6390", "[::1]:6390", "fieldpass", 0},
+	}
+
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Redis.Host = "localhost"
+		cfg.Redis.Port = "6379"
+		cfg.Redis.Password = "fieldpass"
+		cfg.Redis.URL = tt.url
+
+		manager := config.NewManager()
+		if err := manager.LoadConfig(cfg); err != nil {
+			t.Fatalf("%s: failed to install configuration: %v", tt.name, err)
+		}
+		if got := manager.GetRedisAddr(); got != tt.addr {
+			t.Errorf("%s: expected address %q, got %q", tt.name, tt.addr, got)
+		}
+		resolved := manager.GetResolvedRedisConfig()
+		if resolved.Password != tt.password || resolved.DB != tt.db {
+			t.Errorf("%s: expected password %q and db %d, got %q and %d", tt.name, tt.password, tt.db, resolved.Password, resolved.DB)
+		}
+		if got := manager.GetRedisConfig().Password; got != "fieldpass" {
+			t.Errorf("%s: expected the raw config to be unchanged, got password %q", tt.name, got)
+		}
+	}
+
+	if got := config.NewManager().GetResolvedRedisConfig(); got != (config.RedisConfig{}) {
+		t.Errorf("Expected an empty resolved config on an unloaded manager, got %+v", got)
+	}
+}
+
+func TestGetReadReplicaDSNs(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("DATABASE_CONFIG_TYPE", "read_write")
+	t.Setenv("DB_WRITE_HOST", "write.internal")
+	t.Setenv("DB_WRITE_USER", "writer")
+	t.Setenv("DB_WRITE_NAME", "app")
+	t.Setenv("DB_READ_HOST", "read.internal")
+	t.Setenv("DB_READ_USER", "reader")
+	t.Setenv("DB_READ_PASSWORD", "pw")
+	t.Setenv("DB_READ_NAME", "app")
+	t.Setenv("DB_READ_REPLICAS", "replica-1.internal:5432, replica-2.internal:5433")
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	want := []string{"replica-1.internal:5432", "replica-2.internal:5433"}
+	if got := manager.GetDatabaseConfig().ReadReplicas; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected replicas %v, got %v", want, got)
+	}
+
+	dsns := manager.GetReadReplicaDSNs()
+	wantDSNs := []string{
+		"host=replica-1.internal port=5432 user=reader password=pw dbname=app sslmode=disable",
+		"host=replica-2.internal port=5433 user=reader password=pw dbname=app sslmode=disable",
+	}
+	if strings.Join(dsns, "\n") != strings.Join(wantDSNs, "\n") {
+		t.Errorf("Expected DSNs %v, got %v", wantDSNs, dsns)
+	}
+}
+
+func TestGetReadReplicaDSNsAutoDetect(t *testing.T) {
+	cfg := validConfig()
+	cfg.Database.DatabaseConfigType = "auto_detect"
+	cfg.Database.DBWriteHost, cfg.Database.DBReadHost = "write.internal", "read.internal"
+	cfg.Database.ReadReplicas = []string{"replica-1.internal:5432"}
+
+	validator := config.NewValidator()
+	if err := validator.Validate(cfg); err != nil {
+		t.Fatalf("Expected auto-detected read/write configuration to be valid: %v", err)
+	}
+	if warnings := validator.Warnings(); len(warnings) != 0 {
+		t.Errorf("Expected replicas to be used with auto-detected read/write configuration, got %v", warnings)
+	}
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+	dsns := manager.GetReadReplicaDSNs()
+	if len(dsns) != 1 || !strings.Contains(dsns[0], "host=replica-1.internal") || !strings.Contains(dsns[0], "user=postgres") {
+		t.Errorf("Expected a replica DSN with legacy credentials, got %v", dsns)
+	}
+}
+
+func TestGetReadReplicaDSNsLegacy(t *testing.T) {
+	cfg := validConfig()
+	cfg.Database.ReadReplicas = []string{"replica-1.internal:5432"}
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+	if dsns := manager.GetReadReplicaDSNs(); len(dsns) != 0 {
+		t.Errorf("Expected no replica DSNs in legacy mode, got %v", dsns)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	manager.EnableHistory(3)
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	for _, port := range []string{"9001", "9002", "9003", "9004"} {
+		t.Setenv("SERVER_PORT", port)
+		if err := manager.Reload(); err != nil {
+			t.Fatalf("Reload failed: %v", err)
+		}
+	}
+
+	history := manager.History()
+	if len(history) != 3 {
+		t.Fatalf("Expected history capped at 3 entries, got %d", len(history))
+	}
+	for i, port := range []string{"9004", "9003", "9002"} {
+		if got := history[i].Config.Server.Port; got != port {
+			t.Errorf("Entry %d: expected port %s, got %s", i, port, got)
+		}
+		if i > 0 && history[i].Time.After(history[i-1].Time) {
+			t.Errorf("Entry %d is newer than entry %d", i, i-1)
+		}
+	}
+
+	changes := history[0].Changes
+	if len(changes) != 1 || changes[0].Field != "server.port" || changes[0].OldValue != "9003" || changes[0].NewValue != "9004" {
+		t.Errorf("Unexpected changes for the latest entry: %v", changes)
+	}
+
+	if history[0].Config.Database.Password != "[REDACTED]" || history[0].Config.JWT.Secret != "[REDACTED]" {
+		t.Errorf("Expected secrets to be redacted in history, got %+v", history[0].Config)
+	}
+	if manager.GetConfig().Database.Password != "password" {
+		t.Error("Redacting history must not affect the running configuration")
+	}
+}
+
+func TestHistoryRedactsExtensionsAndCopiesMaps(t *testing.T) {
+	cfg := validConfig()
+	cfg.Server.Timeouts = map[string]time.Duration{"upload": time.Minute}
+	cfg.Extensions = map[string]json.RawMessage{
+		"payments":      json.RawMessage(`{"endpoint":"https://pay.example.com","api_secret":"s3cr3t","accounts":[{"password":"hunter2"}]}`),
+		"smtp_password": json.RawMessage(`"hunter2"`),
+	}
+
+	manager := config.NewManager()
+	manager.EnableHistory(2)
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	entry := manager.History()[0].Config
+
+	var payments struct {
+		Endpoint  string `json:"endpoint"`
+		APISecret string `json:"api_secret"`
+		Accounts  []struct {
+			Password string `json:"password"`
+		} `json:"accounts"`
+	}
+	if err := json.Unmarshal(entry.Extensions["payments"], &payments); err != nil {
+		t.Fatalf("Failed to decode redacted extension: %v", err)
+	}
+	if payments.Endpoint != "https://pay.example.com" || payments.APISecret != "[REDACTED]" || payments.Accounts[0].Password != "[REDACTED]" {
+		t.Errorf("Expected only secret extension keys to be redacted, got %+v", payments)
+	}
+	if string(entry.Extensions["smtp_password"]) != `"[REDACTED]"` {
+		t.Errorf("Expected a secret-named extension to be redacted, got %s", entry.Extensions["smtp_password"])
+	}
+	if !strings.Contains(string(manager.GetConfig().Extensions["payments"]), "s3cr3t") {
+		t.Error("Redacting history must not affect the running configuration")
+	}
+
+	manager.GetConfig().Server.Timeouts["upload"] = time.Hour
+	if entry.Server.Timeouts["upload"] != time.Minute {
+		t.Error("History entries must not share maps with the running configuration")
+	}
+
+	cfg.Extensions["payments"] = json.RawMessage(`{"endpoint":"https://pay.example.com","api_secret":"n3w-s3cr3t"}`)
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	changes, _ := json.Marshal(manager.History()[0].Changes)
+	if !strings.Contains(string(changes), "extensions") || strings.Contains(string(changes), "s3cr3t") {
+		t.Errorf("Expected the extension change with its secrets redacted, got %s", changes)
+	}
+}
+
+func TestHistoryDisabled(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if len(manager.History()) != 0 {
+		t.Error("Expected no history unless enabled")
+	}
+
+	manager.EnableHistory(2)
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if len(manager.History()) != 1 {
+		t.Errorf("Expected 1 entry after enabling history, got %d", len(manager.History()))
+	}
+
+	manager.EnableHistory(0)
+	if len(manager.History()) != 0 {
+		t.Error("Expected disabling history to discard entries")
+	}
+}
+
+func TestMarkImmutable(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	manager.MarkImmutable("jwt.issuer")
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Other fields may still change
+	t.Setenv("SERVER_PORT", "9090")
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+
+	t.Setenv("JWT_ISSUER", "other-issuer")
+	t.Setenv("SERVER_PORT", "9091")
+	err := manager.Reload()
+	if err == nil || !strings.Contains(err.Error(), "jwt.issuer") {
+		t.Fatalf("Expected reload changing jwt.issuer to fail naming it, got %v", err)
+	}
+	if cfg := manager.GetConfig(); cfg.JWT.Issuer != "testapp" || cfg.Server.Port != "9090" {
+		t.Errorf("Expected the prior configuration to be kept, got issuer %q port %q", cfg.JWT.Issuer, cfg.Server.Port)
+	}
+
+	if err := manager.SetOverride("jwt.issuer", "override-issuer"); err == nil {
+		t.Error("Expected an override of an immutable field to fail")
+	}
+}
+
+func TestMarkImmutableSection(t *testing.T) {
+	manager := config.NewManager()
+	if err := manager.LoadConfig(validConfig()); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	manager.MarkImmutable("database")
+
+	cfg := validConfig()
+	cfg.Database.Host = "db.example.com"
+	if err := manager.LoadConfig(cfg); err == nil || !strings.Contains(err.Error(), "database.host") {
+		t.Errorf("Expected an error naming database.host, got %v", err)
+	}
+}
+
+// orderWatcher appends its name to a shared log when notified
+type orderWatcher struct {
+	name string
+	log  *[]string
+}
+
+func (w *orderWatcher) OnConfigChanged(oldConfig, newConfig *config.Config) {
+	*w.log = append(*w.log, w.name)
+}
+
+func TestWatcherPriority(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	manager.SetSynchronousNotify(true)
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	var log []string
+	database := &orderWatcher{name: "database", log: &log}
+	manager.AddWatcher(&orderWatcher{name: "default", log: &log})
+	manager.AddWatcherWithPriority(database, 10)
+	manager.AddWatcherWithPriority(&orderWatcher{name: "metrics", log: &log}, -5)
+	manager.AddWatcherWithPriority(&orderWatcher{name: "logging", log: &log}, 100)
+	manager.AddWatcherWithPriority(&orderWatcher{name: "cache", log: &log}, 10)
+
+	t.Setenv("SERVER_PORT", "9090")
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+
+	want := []string{"logging", "database", "cache", "default", "metrics"}
+	if strings.Join(log, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected watchers to run in order %v, got %v", want, log)
+	}
+
+	log = nil
+	manager.RemoveWatcher(database)
+	t.Setenv("SERVER_PORT", "9091")
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if want := "logging,cache,default,metrics"; strings.Join(log, ",") != want {
+		t.Errorf("Expected %s after removing a prioritized watcher, got %v", want, log)
+	}
+}
+
+func TestManagerValidatorStrictMode(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("SERVER_READ_TIMEOUT", "90s")
+	t.Setenv("SERVER_IDLE_TIMEOUT", "60s")
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Expected a timeout warning not to fail the load: %v", err)
+	}
+	if len(manager.Validator().Warnings()) == 0 {
+		t.Fatal("Expected the manager's validator to report the timeout warning")
+	}
+
+	manager.Validator().SetStrict(true)
+	if err := manager.Reload(); err == nil {
+		t.Fatal("Expected strict mode to fail the reload on a warning")
+	}
+
+	strict := config.NewManager()
+	strict.Validator().SetStrict(true)
+	if err := strict.Load(config.EnvironmentStrategy); err == nil {
+		t.Fatal("Expected strict mode to fail the load on a warning")
+	}
+	if strict.IsLoaded() {
+		t.Error("A load rejected in strict mode must not install the configuration")
+	}
+}
+
+func TestManagerWarnings(t *testing.T) {
+	clearConfigEnv(t)
+
+	path := writeConfigFile(t, "config.yaml", strings.Replace(validYAML, `issuer: "testapp"`, `issuer: "testapp${UNSET_ISSUER_SUFFIX}"`, 1)+`
+email:
+  host: "smtp.example.com"
+  port: 5587
+  username: "mailer"
+  from: "noreply@example.com"
+`)
+	t.Setenv("CONFIG_PATH", path)
+
+	manager := config.NewManager()
+	if got := manager.Warnings(); len(got) != 0 {
+		t.Fatalf("Expected no warnings before the first load, got %v", got)
+	}
+	if err := manager.Load(config.FileStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	warnings := strings.Join(manager.Warnings(), "\n")
+	if !strings.Contains(warnings, "UNSET_ISSUER_SUFFIX") {
+		t.Errorf("Expected the loader's unset variable warning, got %q", warnings)
+	}
+	if !strings.Contains(warnings, "5587") {
+		t.Errorf("Expected the validator's SMTP port warning, got %q", warnings)
+	}
+
+	// A rejected load keeps the warnings of the installed configuration
+	if err := manager.LoadConfig(&config.Config{}); err == nil {
+		t.Fatal("Expected an empty configuration to fail validation")
+	}
+	if got := strings.Join(manager.Warnings(), "\n"); got != warnings {
+		t.Errorf("A failed load must not replace the warnings, got %q", got)
+	}
+
+	if err := manager.LoadConfig(validConfig()); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if got := manager.Warnings(); len(got) != 0 {
+		t.Errorf("Expected the warnings to be replaced on the next load, got %v", got)
+	}
+}
+
+func TestManagerEnvironmentRequirements(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("APP_ENVIRONMENT", "production")
+	t.Setenv("EMAIL_HOST", "smtp.example.com")
+	t.Setenv("EMAIL_USERNAME", "user@example.com")
+	t.Setenv("EMAIL_FROM", "noreply@example.com")
+
+	manager := config.NewManager()
+	err := manager.Load(config.EnvironmentStrategy)
+	if !hasFieldError(err, "server.tls_cert_file") || !hasFieldError(err, "server.tls_key_file") {
+		t.Fatalf("Expected production to require TLS files by default, got %v", err)
+	}
+
+	manager.Validator().ClearEnvironmentRequirements("production")
+	manager.Validator().RequireInEnvironment("production", "email.host", "email.from")
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Expected the load to pass once TLS is no longer required: %v", err)
+	}
+}
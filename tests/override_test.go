@@ -0,0 +1,124 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sublimeai21/config"
+)
+
+func TestOverridePinsFieldAcrossReload(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("LOG_LEVEL", "info")
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	if err := manager.SetOverride("log.level", "debug"); err != nil {
+		t.Fatalf("Failed to set override: %v", err)
+	}
+	if got := manager.GetLogConfig().Level; got != "debug" {
+		t.Errorf("Expected override to apply immediately, got %s", got)
+	}
+
+	t.Setenv("LOG_LEVEL", "error")
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Failed to reload configuration: %v", err)
+	}
+	if got := manager.GetLogConfig().Level; got != "debug" {
+		t.Errorf("Expected log level to stay pinned to debug across reload, got %s", got)
+	}
+	if got := manager.FieldSources()["log.level"]; got != config.SourceOverride {
+		t.Errorf("Expected log.level source %q, got %q", config.SourceOverride, got)
+	}
+
+	manager.ClearOverride("log.level")
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Failed to reload configuration: %v", err)
+	}
+	if got := manager.GetLogConfig().Level; got != "error" {
+		t.Errorf("Expected log level from environment after clearing override, got %s", got)
+	}
+}
+
+func TestOverrideBeforeLoad(t *testing.T) {
+	manager := config.NewManager()
+	if err := manager.SetOverride("app.debug", true); err != nil {
+		t.Fatalf("Failed to set override: %v", err)
+	}
+	if err := manager.LoadConfig(validConfig()); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+	if !manager.IsDebug() {
+		t.Error("Expected app.debug override to apply on load")
+	}
+}
+
+func TestOverrideRejectsInvalid(t *testing.T) {
+	manager := config.NewManager()
+	if err := manager.LoadConfig(validConfig()); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+
+	tests := map[string]struct {
+		path  string
+		value interface{}
+	}{
+		"unknown field":    {"log.colour", "red"},
+		"wrong type":       {"app.debug", 42},
+		"unparsable":       {"database.max_conns", "lots"},
+		"fails validation": {"log.level", "verbose"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := manager.SetOverride(tt.path, tt.value); err == nil {
+				t.Errorf("Expected error overriding %s with %v", tt.path, tt.value)
+			}
+		})
+	}
+
+	if len(manager.Overrides()) != 0 {
+		t.Errorf("Rejected overrides must not be stored: %v", manager.Overrides())
+	}
+	if got := manager.GetLogConfig().Level; got != "info" {
+		t.Errorf("Rejected override changed log level to %s", got)
+	}
+}
+
+func TestReloadPreservesOverrides(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("LOG_LEVEL", "info")
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if err := manager.SetOverride("log.level", "warn"); err != nil {
+		t.Fatalf("Failed to set override: %v", err)
+	}
+
+	watcher := newChannelWatcher()
+	manager.AddWatcher(watcher)
+
+	t.Setenv("LOG_LEVEL", "error")
+	t.Setenv("REDIS_HOST", "redis.internal")
+	if err := manager.Reload(); err != nil {
+		t.Fatalf("Failed to reload configuration: %v", err)
+	}
+
+	c := watcher.waitForChange(time.Second)
+	if c == nil {
+		t.Fatal("Watcher was not notified of the reload")
+	}
+	if c.Log.Level != "warn" {
+		t.Errorf("Watcher saw log level %s before the override was reapplied", c.Log.Level)
+	}
+	if got := manager.GetLogConfig().Level; got != "warn" {
+		t.Errorf("Expected overridden log level to survive reload, got %s", got)
+	}
+	if got := manager.GetRedisConfig().Host; got != "redis.internal" {
+		t.Errorf("Expected non-overridden redis host to update, got %s", got)
+	}
+}
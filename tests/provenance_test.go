@@ -0,0 +1,212 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sublimeai21/config"
+)
+
+func TestFieldSourcesHybrid(t *testing.T) {
+	clearConfigEnv(t)
+
+	path := writeConfigFile(t, "config.yaml", strings.Replace(validYAML, `host: "localhost"`, `host: "file-db.internal"`, 1))
+	t.Setenv("CONFIG_PATH", path)
+	t.Setenv("SERVER_PORT", "9090")
+
+	manager := config.NewManager()
+	if err := manager.Load(config.HybridStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	sources := manager.FieldSources()
+	if sources["server.port"] != config.SourceEnv {
+		t.Errorf("Expected server.port from env, got %q", sources["server.port"])
+	}
+	if sources["database.host"] != config.SourceFile {
+		t.Errorf("Expected database.host from file, got %q", sources["database.host"])
+	}
+	if sources["email.host"] != config.SourceDefault {
+		t.Errorf("Expected email.host from defaults, got %q", sources["email.host"])
+	}
+
+	if port := manager.GetServerConfig().Port; port != "9090" {
+		t.Errorf("Expected env port 9090 to win, got %s", port)
+	}
+	if host := manager.GetDatabaseConfig().Host; host != "file-db.internal" {
+		t.Errorf("Expected database host from file, got %s", host)
+	}
+}
+
+func TestFieldSourcesEnvironment(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	sources := manager.FieldSources()
+	if sources["jwt.secret"] != config.SourceEnv {
+		t.Errorf("Expected jwt.secret from env, got %q", sources["jwt.secret"])
+	}
+	if sources["server.read_timeout"] != config.SourceDefault {
+		t.Errorf("Expected server.read_timeout from defaults, got %q", sources["server.read_timeout"])
+	}
+}
+
+func TestSourcePrecedence(t *testing.T) {
+	clearConfigEnv(t)
+
+	path := writeConfigFile(t, "config.yaml", `
+server:
+  port: "7070"
+log:
+  level: "warn"
+`)
+	t.Setenv("CONFIG_PATH", path)
+	t.Setenv("SERVER_PORT", "9090")
+	t.Setenv("REDIS_HOST", "env-redis.internal")
+
+	loader := config.NewLoader()
+	loader.SetSourcePrecedence([]config.SourceKind{config.DefaultsSource, config.EnvSource, config.FileSource})
+	cfg, err := loader.Load(config.HybridStrategy)
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	sources := loader.FieldSources()
+	tests := []struct {
+		field, got, want, source string
+	}{
+		{"server.port", cfg.Server.Port, "7070", config.SourceFile},
+		{"log.level", cfg.Log.Level, "warn", config.SourceFile},
+		{"redis.host", cfg.Redis.Host, "env-redis.internal", config.SourceEnv},
+		{"server.host", cfg.Server.Host, "0.0.0.0", config.SourceDefault},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("Expected %s to be %q, got %q", tt.field, tt.want, tt.got)
+		}
+		if sources[tt.field] != tt.source {
+			t.Errorf("Expected %s from %s, got %q", tt.field, tt.source, sources[tt.field])
+		}
+	}
+
+	// The default order lets the environment win
+	loader.SetSourcePrecedence(nil)
+	cfg, err = loader.Load(config.HybridStrategy)
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.Server.Port != "9090" {
+		t.Errorf("Expected the environment to win by default, got port %s", cfg.Server.Port)
+	}
+}
+
+func TestSourcePrecedenceInvalid(t *testing.T) {
+	clearConfigEnv(t)
+
+	for _, precedence := range [][]config.SourceKind{
+		{config.FileSource, "remote"},
+		{config.EnvSource, config.FileSource, config.EnvSource},
+	} {
+		loader := config.NewLoader()
+		loader.SetSourcePrecedence(precedence)
+		if _, err := loader.Load(config.HybridStrategy); err == nil || !strings.Contains(err.Error(), "invalid source precedence") {
+			t.Errorf("Expected %v to be rejected, got %v", precedence, err)
+		}
+	}
+}
+
+func TestHybridFileLayerErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		mode    os.FileMode
+		want    string
+	}{
+		{"non-UTF-8", "server:\n  host: \"caf\xe9\"\n", 0600, "UTF-8"},
+		{"malformed", "server: [unclosed\n", 0600, "config"},
+		{"readable secrets", validYAML, 0644, "readable by group or others"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv(t)
+			path := writeConfigFile(t, "config.yaml", tt.content)
+			if err := os.Chmod(path, tt.mode); err != nil {
+				t.Fatalf("Failed to chmod config file: %v", err)
+			}
+			t.Setenv("CONFIG_PATH", path)
+
+			loader := config.NewLoader()
+			loader.SetPermissionCheck(true)
+			loader.SetStrict(true)
+			if _, err := loader.Load(config.HybridStrategy); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected a hybrid load error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	clearConfigEnv(t)
+	t.Setenv("CONFIG_PATH", filepath.Join(t.TempDir(), "missing.yaml"))
+	if _, err := config.NewLoader().Load(config.HybridStrategy); err == nil {
+		t.Error("Expected a hybrid load error for a missing config file")
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	clearConfigEnv(t)
+
+	path := writeConfigFile(t, "config.yaml", `
+server:
+  port: "7070"
+  host: "127.0.0.1"
+database:
+  password: "file-password"
+log:
+  level: "warn"
+`)
+	t.Setenv("CONFIG_PATH", path)
+	t.Setenv("SERVER_PORT", "9090")
+	t.Setenv("SERVER_HOST", "127.0.0.1") // same value: not a conflict
+	t.Setenv("DB_PASSWORD", "env-password")
+	t.Setenv("REDIS_HOST", "env-redis.internal")
+	t.Setenv("JWT_SECRET", "test-secret-that-is-long-enough-for-validation")
+
+	manager := config.NewManager()
+	if err := manager.Load(config.HybridStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	want := []config.Conflict{
+		{Field: "database.password", Values: map[string]interface{}{"file": "[REDACTED]", "env": "[REDACTED]"}, Winner: config.SourceEnv},
+		{Field: "server.port", Values: map[string]interface{}{"file": "7070", "env": "9090"}, Winner: config.SourceEnv},
+	}
+	if got := manager.Conflicts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected conflicts %+v, got %+v", want, got)
+	}
+
+	// With the file taking precedence it wins the conflict
+	loader := config.NewLoader()
+	loader.SetSourcePrecedence([]config.SourceKind{config.DefaultsSource, config.EnvSource, config.FileSource})
+	if _, err := loader.Load(config.HybridStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	conflicts := loader.Conflicts()
+	if len(conflicts) != 2 || conflicts[1].Field != "server.port" || conflicts[1].Winner != config.SourceFile {
+		t.Errorf("Expected the file to win the server.port conflict, got %+v", conflicts)
+	}
+
+	// Other strategies report no conflicts
+	if _, err := loader.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if len(loader.Conflicts()) != 0 {
+		t.Errorf("Expected no conflicts after an environment load, got %+v", loader.Conflicts())
+	}
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/sublimeai21/config"
+)
+
+func TestApplyRuntimeConfig(t *testing.T) {
+	previousProcs := runtime.GOMAXPROCS(0)
+	previousLimit := debug.SetMemoryLimit(-1)
+	t.Cleanup(func() {
+		runtime.GOMAXPROCS(previousProcs)
+		debug.SetMemoryLimit(previousLimit)
+	})
+
+	want := previousProcs + 1
+	cfg := validConfig()
+	cfg.Runtime.MaxProcs = want
+	cfg.Runtime.MemoryLimit = "512MiB"
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if err := manager.ApplyRuntimeConfig(); err != nil {
+		t.Fatalf("ApplyRuntimeConfig failed: %v", err)
+	}
+
+	if got := runtime.GOMAXPROCS(0); got != want {
+		t.Errorf("Expected GOMAXPROCS %d, got %d", want, got)
+	}
+	if got := debug.SetMemoryLimit(-1); got != 512<<20 {
+		t.Errorf("Expected memory limit %d, got %d", 512<<20, got)
+	}
+
+	// Zero values leave the runtime untouched
+	cfg = validConfig()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if err := manager.ApplyRuntimeConfig(); err != nil {
+		t.Fatalf("ApplyRuntimeConfig failed: %v", err)
+	}
+	if got := runtime.GOMAXPROCS(0); got != want {
+		t.Errorf("Expected GOMAXPROCS to stay %d, got %d", want, got)
+	}
+
+	runtime.GOMAXPROCS(previousProcs)
+	if got := runtime.GOMAXPROCS(0); got != previousProcs {
+		t.Errorf("Expected GOMAXPROCS restored to %d, got %d", previousProcs, got)
+	}
+}
+
+func TestApplyRuntimeConfigUnloaded(t *testing.T) {
+	if err := config.NewManager().ApplyRuntimeConfig(); err == nil {
+		t.Error("Expected an error without a loaded configuration")
+	}
+}
+
+func TestRuntimeValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *config.Config)
+		field  string
+	}{
+		{"negative max procs", func(cfg *config.Config) { cfg.Runtime.MaxProcs = -1 }, "runtime.max_procs"},
+		{"unknown unit", func(cfg *config.Config) { cfg.Runtime.MemoryLimit = "2 parsecs" }, "runtime.memory_limit"},
+		{"not a number", func(cfg *config.Config) { cfg.Runtime.MemoryLimit = "lots" }, "runtime.memory_limit"},
+		{"zero", func(cfg *config.Config) { cfg.Runtime.MemoryLimit = "0GB" }, "runtime.memory_limit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			err := config.NewValidator().Validate(cfg)
+			if err == nil || !strings.Contains(err.Error(), "runtime") || !hasFieldError(err, tt.field) {
+				t.Errorf("Expected a runtime error for %s, got: %v", tt.field, err)
+			}
+		})
+	}
+
+	for _, limit := range []string{"1048576", "512MiB", "2GB", "1.5 gib"} {
+		cfg := validConfig()
+		cfg.Runtime.MemoryLimit = limit
+		if err := config.NewValidator().Validate(cfg); err != nil {
+			t.Errorf("Expected memory limit %q to be valid, got: %v", limit, err)
+		}
+	}
+}
+
+func TestRuntimeEnv(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("RUNTIME_MAX_PROCS", "3")
+	t.Setenv("RUNTIME_MEMORY_LIMIT", "1GiB")
+
+	cfg, err := config.NewLoader().LoadFromEnvironment()
+	if err != nil {
+		t.Fatalf("LoadFromEnvironment failed: %v", err)
+	}
+	if cfg.Runtime.MaxProcs != 3 || cfg.Runtime.MemoryLimit != "1GiB" {
+		t.Errorf("Unexpected runtime config: %+v", cfg.Runtime)
+	}
+}
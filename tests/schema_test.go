@@ -0,0 +1,151 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sublimeai21/config"
+)
+
+// testSchema requires a long JWT secret and constrains a few other fields
+const testSchema = `{
+  "type": "object",
+  "required": ["jwt", "server"],
+  "properties": {
+    "jwt": {
+      "type": "object",
+      "required": ["secret"],
+      "properties": {
+        "secret": {"type": "string", "minLength": 64}
+      }
+    },
+    "server": {
+      "type": "object",
+      "properties": {
+        "port": {"type": "string", "pattern": "^[0-9]+$"},
+        "read_timeout": {"type": "string"}
+      }
+    },
+    "log": {
+      "type": "object",
+      "properties": {
+        "level": {"enum": ["info", "warn", "error"]}
+      }
+    },
+    "database": {
+      "type": "object",
+      "properties": {
+        "max_conns": {"type": "integer", "maximum": 50}
+      }
+    }
+  }
+}`
+
+func writeSchema(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	return path
+}
+
+func TestValidateAgainstSchema(t *testing.T) {
+	schemaPath := writeSchema(t, testSchema)
+
+	cfg := validConfig()
+	cfg.Log.Level = "debug"
+	cfg.Database.MaxConns = 80
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+
+	err := manager.ValidateAgainstSchema(schemaPath)
+	var validationErr *config.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a *config.ValidationError, got %v", err)
+	}
+
+	want := map[string]string{
+		"jwt.secret":         "jwt.secret must be at least 64 characters long",
+		"log.level":          `log.level must be one of ["info","warn","error"]`,
+		"database.max_conns": "database.max_conns must be at most 50",
+	}
+	if len(validationErr.Fields) != len(want) {
+		t.Errorf("Expected %d violations, got %v", len(want), validationErr.Fields)
+	}
+	for _, fe := range validationErr.Fields {
+		if msg, ok := want[fe.Field]; !ok || fe.Message != msg {
+			t.Errorf("Unexpected violation %+v", fe)
+		}
+	}
+}
+
+func TestValidateAgainstSchemaPasses(t *testing.T) {
+	schemaPath := writeSchema(t, testSchema)
+
+	cfg := validConfig()
+	cfg.JWT.Secret = strings.Repeat("s", 64)
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+	if err := manager.ValidateAgainstSchema(schemaPath); err != nil {
+		t.Errorf("Expected configuration to satisfy the schema: %v", err)
+	}
+}
+
+func TestValidateAgainstSchemaErrors(t *testing.T) {
+	manager := config.NewManager()
+	if err := manager.ValidateAgainstSchema(writeSchema(t, testSchema)); err == nil {
+		t.Error("Expected an error without a loaded configuration")
+	}
+
+	if err := manager.LoadConfig(validConfig()); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+	if err := manager.ValidateAgainstSchema(writeSchema(t, "{not json")); err == nil || !strings.Contains(err.Error(), "invalid schema") {
+		t.Errorf("Expected an invalid schema error, got %v", err)
+	}
+	if err := manager.ValidateAgainstSchema(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected an error for a missing schema file")
+	}
+}
+
+func TestValidateAgainstSchemaUnsupportedKeywords(t *testing.T) {
+	manager := config.NewManager()
+	if err := manager.LoadConfig(validConfig()); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+
+	tests := []struct {
+		schema string
+		want   string
+	}{
+		{`{"$ref": "#/definitions/config"}`, `unsupported keyword "$ref" in schema for config`},
+		{`{"properties": {"jwt": {"allOf": [{"type": "object"}]}}}`, `unsupported keyword "allOf" in schema for jwt`},
+		{`{"properties": {"app": {"properties": {"name": {"type": "string", "format": "hostname"}}}}}`, `unsupported keyword "format" in schema for app.name`},
+		{`{"properties": {"database": {"properties": {"read_replicas": {"items": {"oneOf": []}}}}}}`, `unsupported keyword "oneOf" in schema for database.read_replicas[]`},
+		{`{"properties": {"server": {"additionalProperties": {"anyOf": []}}}}`, `unsupported keyword "anyOf" in schema for server.*`},
+		{`{"properties": {"log": {"properties": {"unused": {"not": {}}}}}}`, `unsupported keyword "not" in schema for log.unused`},
+		{`{"properties": {"jwt": "string"}}`, `schema for jwt must be an object or a boolean`},
+	}
+	for _, tt := range tests {
+		err := manager.ValidateAgainstSchema(writeSchema(t, tt.schema))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Expected %q for %s, got %v", tt.want, tt.schema, err)
+		}
+	}
+
+	annotated := `{"$schema": "https://json-schema.org/draft/2020-12/schema", "title": "Config", "properties": {"app": {"description": "Application settings", "type": "object"}}}`
+	if err := manager.ValidateAgainstSchema(writeSchema(t, annotated)); err != nil {
+		t.Errorf("Expected annotations to be accepted, got %v", err)
+	}
+}
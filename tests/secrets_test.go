@@ -0,0 +1,76 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/sublimeai21/config"
+)
+
+// fakeSecretProvider resolves references from a fixed map
+type fakeSecretProvider map[string]string
+
+func (p fakeSecretProvider) GetSecret(ctx context.Context, ref string) (string, error) {
+	secret, ok := p[ref]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+	return secret, nil
+}
+
+func TestSecretReferenceInFile(t *testing.T) {
+	clearConfigEnv(t)
+
+	yaml := strings.Replace(validYAML, `password: "password"`, `password: "secret://db/password"`, 1)
+	if yaml == validYAML {
+		t.Fatal("Test fixture has no database password to replace")
+	}
+	t.Setenv("CONFIG_PATH", writeConfigFile(t, "config.yaml", yaml))
+
+	loader := config.NewLoader()
+	loader.SetSecretProvider(fakeSecretProvider{"db/password": "s3cr3t"})
+	cfg, err := loader.Load(config.FileStrategy)
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.Database.Password != "s3cr3t" {
+		t.Errorf("Expected the password from the secret provider, got %q", cfg.Database.Password)
+	}
+}
+
+func TestSecretReferenceResolvedBeforeValidation(t *testing.T) {
+	setValidEnv(t)
+	os.Setenv("JWT_SECRET", "secret://jwt")
+
+	manager := config.NewManager()
+	manager.SetSecretProvider(fakeSecretProvider{"jwt": "short"})
+	if err := manager.Load(config.EnvironmentStrategy); err == nil {
+		t.Fatal("Expected the resolved JWT secret to be validated")
+	}
+
+	manager.SetSecretProvider(fakeSecretProvider{"jwt": "resolved-secret-that-is-long-enough-for-validation"})
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if got := manager.GetJWTConfig().Secret; got != "resolved-secret-that-is-long-enough-for-validation" {
+		t.Errorf("Expected the resolved JWT secret, got %q", got)
+	}
+}
+
+func TestSecretReferenceErrors(t *testing.T) {
+	setValidEnv(t)
+	os.Setenv("DB_PASSWORD", "secret://db/missing")
+
+	if _, err := config.NewLoader().Load(config.EnvironmentStrategy); err == nil || !strings.Contains(err.Error(), "no SecretProvider is set") {
+		t.Errorf("Expected an error for a reference without a provider, got %v", err)
+	}
+
+	loader := config.NewLoader()
+	loader.SetSecretProvider(fakeSecretProvider{})
+	if _, err := loader.Load(config.EnvironmentStrategy); err == nil || !strings.Contains(err.Error(), "database.password") {
+		t.Errorf("Expected an error naming the unresolved field, got %v", err)
+	}
+}
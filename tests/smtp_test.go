@@ -0,0 +1,193 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sublimeai21/config"
+)
+
+// startMockSMTP serves a minimal plaintext SMTP dialogue that accepts AUTH
+// PLAIN only for the given credentials, returning the port it listens on
+func startMockSMTP(t *testing.T, username, password string) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start mock SMTP server: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveMockSMTP(conn, username, password)
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func serveMockSMTP(conn net.Conn, username, password string) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	expected := "\x00" + username + "\x00" + password
+
+	reply("220 mock.example.com ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"):
+			reply("250-mock.example.com")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(command, "AUTH PLAIN"):
+			encoded := strings.TrimSpace(strings.TrimPrefix(command, "AUTH PLAIN"))
+			if decodeBase64(encoded) == expected {
+				reply("235 2.7.0 Authentication successful")
+			} else {
+				reply("535 5.7.8 Authentication credentials invalid")
+			}
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func emailManager(t *testing.T, port int, password string) *config.Manager {
+	t.Helper()
+	return emailManagerWithEncryption(t, port, password, "")
+}
+
+func emailManagerWithEncryption(t *testing.T, port int, password, encryption string) *config.Manager {
+	t.Helper()
+
+	cfg := validConfig()
+	cfg.Email = config.EmailConfig{
+		Host:       "127.0.0.1",
+		Port:       port,
+		Username:   "user@example.com",
+		Password:   password,
+		From:       "noreply@example.com",
+		Encryption: encryption,
+	}
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+	return manager
+}
+
+func TestTestEmailConnection(t *testing.T) {
+	port := startMockSMTP(t, "user@example.com", "correct-password")
+	manager := emailManager(t, port, "correct-password")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := manager.TestEmailConnection(ctx); err != nil {
+		t.Errorf("Expected successful SMTP test, got %v", err)
+	}
+}
+
+func TestTestEmailConnectionAuthFailure(t *testing.T) {
+	port := startMockSMTP(t, "user@example.com", "correct-password")
+	manager := emailManager(t, port, "wrong-password")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := manager.TestEmailConnection(ctx)
+	if err == nil {
+		t.Fatal("Expected authentication failure")
+	}
+	if !strings.Contains(err.Error(), "authentication") {
+		t.Errorf("Expected error to name the authentication stage, got %v", err)
+	}
+}
+
+func TestTestEmailConnectionUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to reserve port: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	manager := emailManager(t, port, "password")
+	err = manager.TestEmailConnection(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "failed to connect") {
+		t.Errorf("Expected connection error for port %d, got %v", port, err)
+	}
+}
+
+func TestTestEmailConnectionEncryption(t *testing.T) {
+	port := startMockSMTP(t, "user@example.com", "correct-password")
+
+	tests := []struct {
+		encryption string
+		wantErr    string
+	}{
+		{"", ""},
+		{"none", ""},
+		{"STARTTLS", "does not offer STARTTLS"},
+		{"tls", "TLS handshake"},
+	}
+
+	for _, tt := range tests {
+		manager := emailManagerWithEncryption(t, port, "correct-password", tt.encryption)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := manager.TestEmailConnection(ctx)
+		cancel()
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("Encryption %q: expected successful SMTP test, got %v", tt.encryption, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Encryption %q: expected error containing %q, got %v", tt.encryption, tt.wantErr, err)
+		}
+	}
+}
+
+func TestSMTPEncryptionSetting(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("SMTP_ENCRYPTION", "TLS")
+
+	cfg, err := config.NewLoader().Load(config.EnvironmentStrategy)
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.Email.Encryption != "tls" {
+		t.Errorf("Expected canonical encryption tls, got %q", cfg.Email.Encryption)
+	}
+
+	invalid := validConfig()
+	invalid.Email.Encryption = "ssl"
+	if !hasFieldError(config.NewValidator().Validate(invalid), "email.encryption") {
+		t.Error("Expected an error for an unknown email encryption")
+	}
+}
+
+func decodeBase64(s string) string {
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return ""
+	}
+	return string(decoded)
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/sublimeai21/config"
+)
+
+func TestParsedSSLMode(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected config.SSLMode
+		mysql    string
+	}{
+		{"disable", config.SSLModeDisable, "DISABLED"},
+		{"require", config.SSLModeRequire, "REQUIRED"},
+		{"VERIFY-CA", config.SSLModeVerifyCA, "VERIFY_CA"},
+		{"verify-full", config.SSLModeVerifyFull, "VERIFY_IDENTITY"},
+	}
+
+	for _, tt := range tests {
+		db := config.DatabaseConfig{SSLMode: tt.value}
+		mode, err := db.ParsedSSLMode()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.value, err)
+			continue
+		}
+		if mode != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.value, tt.expected, mode)
+		}
+
+		mysql, err := mode.MySQLMode()
+		if err != nil || mysql != tt.mysql {
+			t.Errorf("%s: expected MySQL mode %s, got %s (%v)", tt.value, tt.mysql, mysql, err)
+		}
+	}
+
+	if _, err := (config.DatabaseConfig{SSLMode: "prefer-maybe"}).ParsedSSLMode(); err == nil {
+		t.Error("Expected an error for an unknown SSL mode")
+	}
+	if _, err := config.SSLMode("bogus").MySQLMode(); err == nil {
+		t.Error("Expected an error mapping an unknown SSL mode")
+	}
+}
@@ -0,0 +1,858 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sublimeai21/config"
+)
+
+func TestCaseInsensitiveEnums(t *testing.T) {
+	cfg := validConfig()
+	cfg.Database.SSLMode = "REQUIRE"
+	cfg.App.Environment = "Production"
+	cfg.Log.Level = "DEBUG"
+	cfg.Email = config.EmailConfig{
+		Host:     "smtp.example.com",
+		Port:     587,
+		Username: "user@example.com",
+		From:     "noreply@example.com",
+	}
+	cfg.Server.TLSCertFile, cfg.Server.TLSKeyFile = "/etc/app/tls.crt", "/etc/app/tls.key"
+
+	if err := config.NewValidator().Validate(cfg); err != nil {
+		t.Fatalf("Validation should accept mixed-case enum values: %v", err)
+	}
+	if errs := config.ValidateAll(map[string]*config.Config{"prod": cfg}); len(errs) != 0 {
+		t.Fatalf("ValidateAll should accept mixed-case enum values: %v", errs)
+	}
+	if cfg.Database.SSLMode != "REQUIRE" || cfg.App.Environment != "Production" || cfg.Log.Level != "DEBUG" {
+		t.Errorf("Validation must not rewrite the caller's configuration, got %s, %s, %s", cfg.Database.SSLMode, cfg.App.Environment, cfg.Log.Level)
+	}
+
+	manager := config.NewManager()
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+	installed := manager.GetConfig()
+	if installed.Database.SSLMode != "require" {
+		t.Errorf("Expected SSL mode normalized to 'require', got %s", installed.Database.SSLMode)
+	}
+	if installed.App.Environment != "production" {
+		t.Errorf("Expected environment normalized to 'production', got %s", installed.App.Environment)
+	}
+	if installed.Log.Level != "debug" {
+		t.Errorf("Expected log level normalized to 'debug', got %s", installed.Log.Level)
+	}
+}
+
+func TestCaseInsensitiveEnvironmentFromEnv(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("DB_SSL_MODE", "REQUIRE")
+	t.Setenv("APP_ENVIRONMENT", "Production")
+	t.Setenv("EMAIL_HOST", "smtp.example.com")
+	t.Setenv("EMAIL_USERNAME", "user@example.com")
+	t.Setenv("EMAIL_FROM", "noreply@example.com")
+	t.Setenv("SERVER_TLS_CERT_FILE", "/etc/app/tls.crt")
+	t.Setenv("SERVER_TLS_KEY_FILE", "/etc/app/tls.key")
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if !manager.IsProduction() {
+		t.Error("Expected IsProduction() to be true for APP_ENVIRONMENT=Production")
+	}
+	if manager.GetDatabaseConfig().SSLMode != "require" {
+		t.Errorf("Expected normalized SSL mode, got %s", manager.GetDatabaseConfig().SSLMode)
+	}
+}
+
+func TestInvalidEnumStillRejected(t *testing.T) {
+	cfg := validConfig()
+	cfg.Database.SSLMode = "sometimes"
+
+	if err := config.NewValidator().Validate(cfg); err == nil {
+		t.Error("Validation should reject an unknown SSL mode")
+	}
+}
+
+func TestValidatePooled(t *testing.T) {
+	if err := config.ValidatePooled(validConfig()); err != nil {
+		t.Errorf("Pooled validation should pass for a valid config: %v", err)
+	}
+
+	invalid := validConfig()
+	invalid.JWT.Secret = "short"
+	if err := config.ValidatePooled(invalid); err == nil {
+		t.Error("Pooled validation should fail for an invalid config")
+	}
+
+	// Errors from one call must not leak into the next
+	if err := config.ValidatePooled(validConfig()); err != nil {
+		t.Errorf("Pooled validator retained errors from a previous call: %v", err)
+	}
+}
+
+func TestValidateCurrentConcurrent(t *testing.T) {
+	setValidEnv(t)
+
+	manager := config.NewManager()
+	if err := manager.Load(config.EnvironmentStrategy); err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if err := manager.ValidateCurrent(); err != nil {
+					t.Errorf("ValidateCurrent failed: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkValidateNewValidator(b *testing.B) {
+	cfg := validConfig()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = config.NewValidator().Validate(cfg)
+	}
+}
+
+func BenchmarkValidatePooled(b *testing.B) {
+	cfg := validConfig()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = config.ValidatePooled(cfg)
+	}
+}
+
+func TestEmailPortWarning(t *testing.T) {
+	tests := []struct {
+		port        int
+		wantWarning bool
+	}{
+		{587, false},
+		{465, false},
+		{2525, false},
+		{5587, true},
+	}
+
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Email = config.EmailConfig{
+			Host:     "smtp.example.com",
+			Port:     tt.port,
+			Username: "user@example.com",
+			From:     "noreply@example.com",
+		}
+
+		validator := config.NewValidator()
+		if err := validator.Validate(cfg); err != nil {
+			t.Errorf("Port %d: email port warning must not fail validation: %v", tt.port, err)
+		}
+
+		warnings := validator.Warnings()
+		if got := len(warnings) > 0; got != tt.wantWarning {
+			t.Errorf("Port %d: expected warning=%t, got %v", tt.port, tt.wantWarning, warnings)
+		}
+	}
+}
+
+func TestJWTIssuerFormat(t *testing.T) {
+	tests := []struct {
+		issuer string
+		valid  bool
+	}{
+		{"https://auth.example.com", true},
+		{"https://auth.example.com/realms/main", true},
+		{"auth-service", true},
+		{"my app", false},
+		{"https://", false},
+	}
+
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.JWT.Issuer = tt.issuer
+
+		validator := config.NewValidator()
+		validator.SetIssuerFormatCheck(true)
+		err := validator.Validate(cfg)
+		if (err == nil) != tt.valid {
+			t.Errorf("Issuer %q: expected valid=%t, got %v", tt.issuer, tt.valid, err)
+		}
+	}
+
+	// The check is off by default
+	cfg := validConfig()
+	cfg.JWT.Issuer = "my app"
+	if err := config.NewValidator().Validate(cfg); err != nil {
+		t.Errorf("Issuer format should not be checked by default: %v", err)
+	}
+}
+
+func TestConflictingDatabaseLayouts(t *testing.T) {
+	conflicting := func() *config.Config {
+		cfg := validConfig()
+		cfg.Database.Host = "legacy-db.internal"
+		cfg.Database.DBWriteHost = "write-db.internal"
+		cfg.Database.DBReadHost = "read-db.internal"
+		return cfg
+	}
+
+	for _, configType := range []string{"", "auto_detect"} {
+		cfg := conflicting()
+		cfg.Database.DatabaseConfigType = configType
+		err := config.NewValidator().Validate(cfg)
+		if err == nil || !strings.Contains(err.Error(), "choose one") {
+			t.Errorf("Config type %q: expected a conflict error, got %v", configType, err)
+		}
+	}
+
+	// An explicit type resolves the ambiguity
+	cfg := conflicting()
+	cfg.Database.DatabaseConfigType = "legacy"
+	if err := config.NewValidator().Validate(cfg); err != nil {
+		t.Errorf("Explicit legacy type should not conflict: %v", err)
+	}
+
+	// The legacy default host alone does not count as set
+	cfg = validConfig()
+	cfg.Database.DatabaseConfigType = "auto_detect"
+	cfg.Database.DBWriteHost = "write-db.internal"
+	if err := config.NewValidator().Validate(cfg); err != nil {
+		t.Errorf("Default legacy host should not conflict: %v", err)
+	}
+
+	// A legacy host set explicitly to the default value still counts as set
+	setValidEnv(t)
+	t.Setenv("DB_WRITE_HOST", "write-db.internal")
+	err := config.NewManager().Load(config.EnvironmentStrategy)
+	if err == nil || !strings.Contains(err.Error(), "choose one") {
+		t.Errorf("Expected DB_HOST=localhost next to DB_WRITE_HOST to conflict, got %v", err)
+	}
+	t.Setenv("DB_HOST", "")
+	if err := config.NewManager().Load(config.EnvironmentStrategy); err != nil {
+		t.Errorf("Empty DB_HOST should not conflict: %v", err)
+	}
+}
+
+func TestRedisURLDatabaseConsistency(t *testing.T) {
+	tests := []struct {
+		name  string
+		url   string
+		db    int
+		valid bool
+	}{
+		{"matching", "redis://localhost:6379/2", 2, true},
+		{"url only", "redis://localhost:6379/3", 0, true},
+		{"no path", "redis://localhost:6379", 5, true},
+		{"conflicting", "redis://localhost:6379/3", 2, false},
+		{"bad path", "redis://localhost:6379/abc", 0, false},
+		{"bad scheme", "http://localhost:6379/1", 0, false},
+	}
+
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Redis.URL = tt.url
+		cfg.Redis.DB = tt.db
+
+		err := config.NewValidator().Validate(cfg)
+		if (err == nil) != tt.valid {
+			t.Errorf("%s: expected valid=%t, got %v", tt.name, tt.valid, err)
+		}
+	}
+}
+
+func TestRedisPoolValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		poolSize int
+		minIdle  int
+		field    string
+	}{
+		{"valid", 10, 5, ""},
+		{"client default pool", 0, 5, ""},
+		{"min idle equals pool", 10, 10, ""},
+		{"min idle exceeds pool", 5, 10, "redis.min_idle_conns"},
+		{"negative pool", -1, 0, "redis.pool_size"},
+		{"negative min idle", 10, -1, "redis.min_idle_conns"},
+	}
+
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Redis.PoolSize = tt.poolSize
+		cfg.Redis.MinIdleConns = tt.minIdle
+
+		err := config.NewValidator().Validate(cfg)
+		if tt.field == "" {
+			if err != nil {
+				t.Errorf("%s: expected valid, got %v", tt.name, err)
+			}
+			continue
+		}
+		if !hasFieldError(err, tt.field) {
+			t.Errorf("%s: expected an error for %s, got %v", tt.name, tt.field, err)
+		}
+	}
+}
+
+func TestEmailFromDomainAllowlist(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    string
+		allowed []string
+		valid   bool
+	}{
+		{"allowed domain", "noreply@example.com", []string{"example.com"}, true},
+		{"allowed domain with display name", "My App <noreply@Example.com>", []string{"other.com", "example.com"}, true},
+		{"disallowed domain", "noreply@attacker.com", []string{"example.com"}, false},
+		{"subdomain not listed", "noreply@mail.example.com", []string{"example.com"}, false},
+		{"invalid address", "not-an-address", []string{"example.com"}, false},
+		{"empty allowlist", "noreply@anything.com", nil, true},
+	}
+
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Email = config.EmailConfig{Host: "smtp.example.com", Port: 587, Username: "user", From: tt.from, AllowedFromDomains: tt.allowed}
+
+		err := config.NewValidator().Validate(cfg)
+		if tt.valid && err != nil {
+			t.Errorf("%s: expected valid, got %v", tt.name, err)
+		}
+		if !tt.valid && !hasFieldError(err, "email.from") {
+			t.Errorf("%s: expected an email.from error, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestPortRanges(t *testing.T) {
+	setters := map[string]func(*config.Config, string){
+		"server":   func(c *config.Config, port string) { c.Server.Port = port },
+		"database": func(c *config.Config, port string) { c.Database.Port = port },
+		"redis":    func(c *config.Config, port string) { c.Redis.Port = port },
+	}
+	tests := []struct {
+		port    string
+		wantErr bool
+	}{
+		{"0", true},
+		{"70000", true},
+		{"8080", false},
+	}
+
+	for name, set := range setters {
+		for _, tt := range tests {
+			cfg := validConfig()
+			set(cfg, tt.port)
+
+			err := config.NewValidator().Validate(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("%s port %s: expected error=%t, got %v", name, tt.port, tt.wantErr, err)
+			}
+			if tt.wantErr && err != nil && !strings.Contains(err.Error(), "between 1 and 65535") {
+				t.Errorf("%s port %s: expected range error, got %v", name, tt.port, err)
+			}
+		}
+	}
+}
+
+func TestValidationTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*config.Config)
+		wantErr string
+	}{
+		{"valid", func(c *config.Config) {}, ""},
+		{"negative max procs", func(c *config.Config) { c.Runtime.MaxProcs = -1 }, "runtime.max_procs must be at least 0"},
+		{"negative pool size", func(c *config.Config) { c.Redis.PoolSize = -1 }, "redis.pool_size must be at least 0"},
+		{"missing app version", func(c *config.Config) { c.App.Version = "" }, "app.version is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			err := config.NewValidator().Validate(cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Expected error %q, got nil", tt.wantErr)
+			}
+			validationErr, ok := err.(*config.ValidationError)
+			if !ok {
+				t.Fatalf("Expected *config.ValidationError, got %T", err)
+			}
+			if len(validationErr.Errors) != 1 || validationErr.Errors[0] != tt.wantErr {
+				t.Errorf("Expected only %q, got %v", tt.wantErr, validationErr.Errors)
+			}
+		})
+	}
+}
+
+func TestRequiredJWTSecretAndAppName(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*config.Config)
+		wantErr string
+	}{
+		{"missing JWT secret", func(c *config.Config) { c.JWT.Secret = "" }, "JWT secret is required"},
+		{"short JWT secret", func(c *config.Config) { c.JWT.Secret = "too-short" }, "JWT secret must be at least 32 characters long"},
+		{"missing app name", func(c *config.Config) { c.App.Name = "" }, "application name is required"},
+	}
+
+	for _, tt := range tests {
+		cfg := validConfig()
+		tt.modify(cfg)
+
+		err := config.NewValidator().Validate(cfg)
+		validationErr, ok := err.(*config.ValidationError)
+		if !ok {
+			t.Fatalf("%s: expected *config.ValidationError, got %v", tt.name, err)
+		}
+		if len(validationErr.Errors) != 1 || validationErr.Errors[0] != tt.wantErr {
+			t.Errorf("%s: expected only %q, got %v", tt.name, tt.wantErr, validationErr.Errors)
+		}
+	}
+}
+
+func TestServerTimeoutOrdering(t *testing.T) {
+	tests := []struct {
+		name         string
+		read, write  time.Duration
+		idle         time.Duration
+		wantWarnings int
+	}{
+		{"sensible", 30 * time.Second, 30 * time.Second, 60 * time.Second, 0},
+		{"read exceeds idle", 2 * time.Minute, 30 * time.Second, 60 * time.Second, 1},
+		{"both exceed idle", 2 * time.Minute, 2 * time.Minute, 60 * time.Second, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Server.ReadTimeout = tt.read
+			cfg.Server.WriteTimeout = tt.write
+			cfg.Server.IdleTimeout = tt.idle
+
+			validator := config.NewValidator()
+			if err := validator.Validate(cfg); err != nil {
+				t.Fatalf("Timeout ordering must not fail validation: %v", err)
+			}
+			if got := validator.Warnings(); len(got) != tt.wantWarnings {
+				t.Errorf("Expected %d warnings, got %v", tt.wantWarnings, got)
+			}
+
+			strict := config.NewValidator()
+			strict.SetStrict(true)
+			err := strict.Validate(cfg)
+			if (err != nil) != (tt.wantWarnings > 0) {
+				t.Errorf("Strict mode: expected error=%t, got %v", tt.wantWarnings > 0, err)
+			}
+		})
+	}
+}
+
+func TestProductionRequiredFields(t *testing.T) {
+	cfg := validConfig()
+	validator := config.NewValidator()
+
+	cfg.App.Environment = "development"
+	if err := validator.Validate(cfg); err != nil {
+		t.Fatalf("Expected configuration to be valid in development: %v", err)
+	}
+
+	cfg.App.Environment = "production"
+	err := validator.Validate(cfg)
+	if err == nil {
+		t.Fatal("Expected production validation to require email and TLS settings")
+	}
+	for _, want := range []string{
+		"email.host is required in production",
+		"email.from is required in production",
+		"server.tls_cert_file is required in production",
+		"server.tls_key_file is required in production",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in %v", want, err)
+		}
+	}
+
+	cfg.Email = config.EmailConfig{
+		Host:     "smtp.example.com",
+		Port:     587,
+		Username: "user@example.com",
+		From:     "noreply@example.com",
+	}
+	cfg.Server.TLSCertFile = "/etc/app/tls.crt"
+	cfg.Server.TLSKeyFile = "/etc/app/tls.key"
+	if err := validator.Validate(cfg); err != nil {
+		t.Errorf("Expected complete production configuration to be valid: %v", err)
+	}
+
+	cfg.JWT.Secret = "change-me-to-a-random-secret-of-at-least-32-characters"
+	if err := validator.Validate(cfg); err == nil {
+		t.Error("Expected production validation to reject the example JWT secret")
+	}
+}
+
+func TestEnvironmentRequirementsOptOut(t *testing.T) {
+	cfg := validConfig()
+	cfg.App.Environment = "production"
+
+	validator := config.NewValidator()
+	validator.ClearEnvironmentRequirements("Production")
+	if err := validator.Validate(cfg); err != nil {
+		t.Fatalf("Expected no production requirements after opting out: %v", err)
+	}
+
+	validator.RequireInEnvironment("PRODUCTION", "email.host")
+	err := validator.Validate(cfg)
+	if !hasFieldError(err, "email.host") {
+		t.Errorf("Expected the re-registered email.host requirement, got %v", err)
+	}
+	if hasFieldError(err, "server.tls_cert_file") {
+		t.Errorf("Expected the TLS requirement to stay cleared, got %v", err)
+	}
+}
+
+func TestPlaceholderJWTSecret(t *testing.T) {
+	for _, secret := range []string{
+		"your-secret-key",
+		"change-me-to-a-random-secret-of-at-least-32-characters",
+		"your-super-secret-jwt-key-that-is-at-least-32-characters-long",
+	} {
+		cfg := validConfig()
+		cfg.JWT.Secret = secret
+		cfg.App.Environment = "production"
+		cfg.Email = config.EmailConfig{Host: "smtp.example.com", Port: 587, Username: "user", From: "noreply@example.com"}
+		cfg.Server.TLSCertFile, cfg.Server.TLSKeyFile = "/etc/app/tls.crt", "/etc/app/tls.key"
+
+		err := config.NewValidator().Validate(cfg)
+		if err == nil || !strings.Contains(err.Error(), "JWT secret must be changed from its default value in production") {
+			t.Errorf("Expected production validation to reject placeholder secret %q, got %v", secret, err)
+		}
+	}
+}
+
+func TestPlaceholderJWTSecretWarnsOutsideProduction(t *testing.T) {
+	cfg := validConfig()
+	cfg.JWT.Secret = "your-super-secret-jwt-key-that-is-at-least-32-characters-long"
+	cfg.App.Environment = "development"
+
+	validator := config.NewValidator()
+	if err := validator.Validate(cfg); err != nil {
+		t.Fatalf("Expected placeholder secret to be allowed in development: %v", err)
+	}
+
+	found := false
+	for _, w := range validator.Warnings() {
+		if strings.Contains(w, "JWT secret is a well-known placeholder") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected a placeholder JWT secret warning, got %v", validator.Warnings())
+	}
+}
+
+func TestRequireInEnvironment(t *testing.T) {
+	cfg := validConfig()
+	cfg.App.Environment = "staging"
+	cfg.Log.OutputPath = ""
+
+	validator := config.NewValidator()
+	validator.RequireInEnvironment("staging", "log.output_path")
+
+	err := validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "log.output_path is required in staging") {
+		t.Errorf("Expected staging requirement error, got %v", err)
+	}
+
+	cfg.App.Environment = "test"
+	if err := validator.Validate(cfg); err != nil {
+		t.Errorf("Requirement must only apply to staging, got %v", err)
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	invalid := validConfig()
+	invalid.Server.Port = "70000"
+
+	results := config.ValidateAll(map[string]*config.Config{
+		"dev":     validConfig(),
+		"staging": invalid,
+		"prod":    nil,
+	})
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 failures, got %v", results)
+	}
+	if _, ok := results["dev"]; ok {
+		t.Errorf("Valid config should not be reported, got %v", results["dev"])
+	}
+	if err := results["staging"]; err == nil || !strings.Contains(err.Error(), "server port must be between 1 and 65535") {
+		t.Errorf("Expected port error for staging, got %v", err)
+	}
+	if results["prod"] == nil {
+		t.Error("Expected error for nil prod config")
+	}
+
+	if results := config.ValidateAll(map[string]*config.Config{"dev": validConfig()}); len(results) != 0 {
+		t.Errorf("Expected no failures, got %v", results)
+	}
+}
+
+func TestAppNameCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		wantWarning bool
+	}{
+		{"my_service", false},
+		{"MyService2", false},
+		{"My Service", true},
+		{"api-gateway", true},
+	}
+
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.App.Name = tt.name
+
+		validator := config.NewValidator()
+		validator.SetAppNameCheck(true)
+		if err := validator.Validate(cfg); err != nil {
+			t.Errorf("%q: app name check must not fail validation: %v", tt.name, err)
+		}
+		if got := len(validator.Warnings()) > 0; got != tt.wantWarning {
+			t.Errorf("%q: expected warning=%t, got %v", tt.name, tt.wantWarning, validator.Warnings())
+		}
+	}
+
+	cfg := validConfig()
+	cfg.App.Name = "My Service"
+	validator := config.NewValidator()
+	if err := validator.Validate(cfg); err != nil || len(validator.Warnings()) != 0 {
+		t.Errorf("App name check should be off by default, got err=%v warnings=%v", err, validator.Warnings())
+	}
+}
+
+func TestBindAllCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		host        string
+		bindAll     bool
+		wantWarning bool
+	}{
+		{"production 0.0.0.0", "production", "0.0.0.0", false, true},
+		{"production 0.0.0.0 acknowledged", "production", "0.0.0.0", true, false},
+		{"production specific host", "production", "10.0.0.5", false, false},
+		{"development 0.0.0.0", "development", "0.0.0.0", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.App.Environment = tt.environment
+			cfg.Email = config.EmailConfig{Host: "smtp.example.com", Port: 587, Username: "user", From: "noreply@example.com"}
+			cfg.Server.TLSCertFile, cfg.Server.TLSKeyFile = "/etc/app/tls.crt", "/etc/app/tls.key"
+			cfg.Server.Host = tt.host
+			cfg.Server.BindAll = tt.bindAll
+
+			validator := config.NewValidator()
+			validator.SetBindAllCheck(true)
+			if err := validator.Validate(cfg); err != nil {
+				t.Fatalf("Bind-all check must not fail validation: %v", err)
+			}
+
+			found := false
+			for _, warning := range validator.Warnings() {
+				found = found || strings.Contains(warning, "SERVER_BIND_ALL")
+			}
+			if found != tt.wantWarning {
+				t.Errorf("Expected warning=%t, got %v", tt.wantWarning, validator.Warnings())
+			}
+		})
+	}
+
+	cfg := validConfig()
+	cfg.App.Environment = "production"
+	cfg.Email = config.EmailConfig{Host: "smtp.example.com", Port: 587, Username: "user", From: "noreply@example.com"}
+	cfg.Server.TLSCertFile, cfg.Server.TLSKeyFile = "/etc/app/tls.crt", "/etc/app/tls.key"
+	cfg.Server.Host = "0.0.0.0"
+	validator := config.NewValidator()
+	if err := validator.Validate(cfg); err != nil || len(validator.Warnings()) != 0 {
+		t.Errorf("Bind-all check should be off by default, got err=%v warnings=%v", err, validator.Warnings())
+	}
+}
+
+func TestMetricSafeName(t *testing.T) {
+	tests := map[string]string{
+		"my_service":    "my_service",
+		"My Service":    "my_service",
+		"API-Gateway  ": "api_gateway",
+		"My App (EU)":   "my_app_eu",
+		"2fa service":   "_2fa_service",
+		"!!!":           "_",
+	}
+
+	for input, want := range tests {
+		if got := config.MetricSafeName(input); got != want {
+			t.Errorf("MetricSafeName(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	manager := config.NewManager()
+	cfg := validConfig()
+	cfg.App.Name = "User Service"
+	if err := manager.LoadConfig(cfg); err != nil {
+		t.Fatalf("Failed to install configuration: %v", err)
+	}
+	if got := manager.MetricSafeAppName(); got != "user_service" {
+		t.Errorf("Expected user_service, got %q", got)
+	}
+}
+
+func TestDeprecatedFieldWarning(t *testing.T) {
+	config.RegisterDeprecatedField("log.output_path", "log.file", "2.0.0")
+
+	cfg := validConfig()
+	validator := config.NewValidator()
+	if err := validator.Validate(cfg); err != nil {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+	if warnings := validator.Warnings(); len(warnings) != 0 {
+		t.Errorf("Unset deprecated field should not warn, got %v", warnings)
+	}
+
+	cfg.Log.OutputPath = "/var/log/app.log"
+	if err := validator.Validate(cfg); err != nil {
+		t.Fatalf("Deprecated fields must not fail validation: %v", err)
+	}
+	warnings := validator.Warnings()
+	if len(warnings) != 1 {
+		t.Fatalf("Expected 1 deprecation warning, got %v", warnings)
+	}
+	if want := "log.output_path is deprecated since 2.0.0; use log.file instead"; warnings[0] != want {
+		t.Errorf("Expected %q, got %q", want, warnings[0])
+	}
+}
+
+func TestValidationErrorJSON(t *testing.T) {
+	cfg := validConfig()
+	cfg.Server.Port = ""
+	cfg.Log.Level = "loud"
+
+	err := config.NewValidator().Validate(cfg)
+	var validationErr *config.ValidationError
+	if !errors.As(fmt.Errorf("startup: %w", err), &validationErr) {
+		t.Fatalf("Expected errors.As to extract *config.ValidationError from %v", err)
+	}
+
+	data, err := json.Marshal(validationErr)
+	if err != nil {
+		t.Fatalf("Failed to marshal validation error: %v", err)
+	}
+
+	var payload struct {
+		Errors []struct {
+			Field   string `json:"field"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("Failed to decode %s: %v", data, err)
+	}
+	if len(payload.Errors) != 2 {
+		t.Fatalf("Expected 2 errors, got %s", data)
+	}
+	if payload.Errors[0].Field != "server.port" || payload.Errors[0].Message != "server port is required" {
+		t.Errorf("Unexpected first error: %+v", payload.Errors[0])
+	}
+	if payload.Errors[1].Field != "log.level" || !strings.HasPrefix(payload.Errors[1].Message, "log level must be one of") {
+		t.Errorf("Unexpected second error: %+v", payload.Errors[1])
+	}
+}
+
+func TestValidationErrorJSONWithoutFields(t *testing.T) {
+	data, err := json.Marshal(&config.ValidationError{Errors: []string{"something is wrong"}})
+	if err != nil {
+		t.Fatalf("Failed to marshal validation error: %v", err)
+	}
+	if want := `{"errors":[{"field":"","message":"something is wrong"}]}`; string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+}
+
+func TestReadReplicaValidation(t *testing.T) {
+	cfg := validConfig()
+	cfg.Database.ReadReplicas = []string{"replica-1.internal:5432", "replica-2.internal", "replica-3.internal:99999"}
+
+	err := config.NewValidator().Validate(cfg)
+	if err == nil {
+		t.Fatal("Expected malformed read replicas to be rejected")
+	}
+	for _, want := range []string{
+		`read replica 2 ("replica-2.internal") must be a host:port address`,
+		`read replica 3 ("replica-3.internal:99999") port must be between 1 and 65535`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in %v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "read replica 1") {
+		t.Errorf("Expected the well-formed replica to pass, got %v", err)
+	}
+}
+
+func TestValidateConnectionStringDialTimeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	validator := config.NewValidator()
+	validator.SetDialTimeout(50 * time.Millisecond)
+	if err := validator.ValidateConnectionString(host, port); err != nil {
+		t.Errorf("Expected a listening address to be reachable: %v", err)
+	}
+
+	// Once closed, the port no longer accepts connections
+	listener.Close()
+
+	start := time.Now()
+	if err := validator.ValidateConnectionString(host, port); err == nil {
+		t.Fatal("Expected an error for a non-accepting address")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the dial to fail fast, took %s", elapsed)
+	}
+
+	start = time.Now()
+	err = validator.ValidateConnectionStringWithRetry(host, port, 3, 10*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Errorf("Expected a failure after 3 attempts, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected each attempt to respect the dial timeout, took %s", elapsed)
+	}
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sublimeai21/config"
+)
+
+func TestWebhookWatcherPostsRedactedDiff(t *testing.T) {
+	payloads := make(chan config.WebhookPayload, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload config.WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode webhook payload: %v", err)
+		}
+		payloads <- payload
+	}))
+	defer server.Close()
+
+	oldConfig := validConfig()
+	newConfig := validConfig()
+	newConfig.Log.Level = "debug"
+	newConfig.Database.Password = "new-password"
+
+	watcher := config.NewWebhookWatcher(server.URL, server.Client())
+	watcher.OnConfigChanged(oldConfig, newConfig)
+
+	var payload config.WebhookPayload
+	select {
+	case payload = <-payloads:
+	case <-time.After(time.Second):
+		t.Fatal("Webhook did not receive a payload")
+	}
+
+	if len(payload.Changes) != 2 {
+		t.Fatalf("Expected 2 changes, got %v", payload.Changes)
+	}
+	for _, change := range payload.Changes {
+		switch change.Field {
+		case "log.level":
+			if change.OldValue != "info" || change.NewValue != "debug" {
+				t.Errorf("Unexpected log level change: %+v", change)
+			}
+		case "database.password":
+			if change.OldValue != "[REDACTED]" || change.NewValue != "[REDACTED]" {
+				t.Errorf("Database password was not redacted: %+v", change)
+			}
+		default:
+			t.Errorf("Unexpected change: %+v", change)
+		}
+	}
+}
+
+func TestWebhookWatcherRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	newConfig := validConfig()
+	newConfig.Log.Level = "debug"
+
+	var deliveryErr error
+	watcher := config.NewWebhookWatcher(server.URL, server.Client())
+	watcher.SetRetry(3, 0)
+	watcher.SetErrorHandler(func(err error) { deliveryErr = err })
+	watcher.OnConfigChanged(validConfig(), newConfig)
+
+	if deliveryErr != nil {
+		t.Errorf("Expected delivery to succeed on the third attempt, got %v", deliveryErr)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+
+	atomic.StoreInt32(&calls, -10)
+	watcher.SetRetry(2, 0)
+	watcher.OnConfigChanged(validConfig(), newConfig)
+	if deliveryErr == nil {
+		t.Error("Expected an error after exhausting retries")
+	}
+}
+
+func TestWebhookWatcherTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	newConfig := validConfig()
+	newConfig.Log.Level = "debug"
+
+	var deliveryErr error
+	watcher := config.NewWebhookWatcher(server.URL, server.Client())
+	watcher.SetTimeout(50 * time.Millisecond)
+	watcher.SetRetry(1, 0)
+	watcher.SetErrorHandler(func(err error) { deliveryErr = err })
+	watcher.OnConfigChanged(validConfig(), newConfig)
+
+	if deliveryErr == nil {
+		t.Error("Expected a timeout error")
+	}
+}
@@ -1,29 +1,178 @@
 package config
 
 import (
+	"crypto"
+	"encoding/json"
 	"fmt"
 	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
 )
 
+// Accepted values for enum-like configuration fields, in canonical casing
+var (
+	validDatabaseConfigTypes = []string{"read_write", "legacy", "auto_detect"}
+	validSSLModes            = []string{string(SSLModeDisable), string(SSLModeRequire), string(SSLModeVerifyCA), string(SSLModeVerifyFull)}
+	validLogLevels           = []string{"debug", "info", "warn", "warning", "error", "fatal", "panic"}
+	validLogFormats          = []string{"json", "text", "console"}
+	validEnvironments        = []string{"development", "staging", "production", "test"}
+	validSMTPEncryptions     = []string{smtpEncryptionNone, smtpEncryptionStartTLS, smtpEncryptionTLS}
+	commonSMTPPorts          = []int{25, 465, 587, 2525}
+)
+
+// builtinRequiredFields lists fields that the explicit validation rules
+// require rather than a `validate:"required"` tag. jwt.secret is only required
+// for HS* algorithms.
+var builtinRequiredFields = []string{"jwt.secret", "app.name"}
+
+// defaultEnvironmentRequiredFields lists the fields NewValidator requires per
+// environment, on top of those required everywhere. Callers that do without
+// them, such as services behind a TLS-terminating proxy or without email,
+// opt out with ClearEnvironmentRequirements.
+var defaultEnvironmentRequiredFields = map[string][]string{
+	"production": {"server.tls_cert_file", "server.tls_key_file", "email.host", "email.from"},
+}
+
+// defaultDialTimeout bounds connection attempts unless SetDialTimeout is called
+const defaultDialTimeout = 5 * time.Second
+
+// metricSafeNamePattern matches names usable as-is in logging and metrics
+// labels, following Prometheus naming conventions
+var metricSafeNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
 // Validator provides configuration validation functionality
 type Validator struct {
-	errors []string
+	mutex    sync.Mutex
+	errors   []FieldError
+	warnings []FieldError
+
+	checkIssuerFormat bool
+	checkAppName      bool
+	checkBindAll      bool
+	strict            bool
+
+	// dialTimeout bounds each connection attempt of ValidateConnectionString
+	dialTimeout time.Duration
+
+	// requiredFields holds the fields required per environment
+	requiredFields map[string][]string
+
+	// sources holds the field sources of the configuration being validated,
+	// if known, keyed by dotted field path
+	sources map[string]string
 }
 
 // NewValidator creates a new validator instance
 func NewValidator() *Validator {
+	requiredFields := make(map[string][]string, len(defaultEnvironmentRequiredFields))
+	for env, fields := range defaultEnvironmentRequiredFields {
+		requiredFields[env] = cloneStrings(fields)
+	}
 	return &Validator{
-		errors: make([]string, 0),
+		errors:         make([]FieldError, 0),
+		requiredFields: requiredFields,
+		dialTimeout:    defaultDialTimeout,
+	}
+}
+
+// RequireInEnvironment marks fields, given as dotted paths such as
+// "email.host", as required when App.Environment is env. env is matched
+// case-insensitively, like App.Environment itself. By default production
+// requires the TLS certificate and key files and the email host and sender.
+func (v *Validator) RequireInEnvironment(env string, fields ...string) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	canonicalize(&env, validEnvironments)
+	v.requiredFields[env] = append(v.requiredFields[env], fields...)
+}
+
+// ClearEnvironmentRequirements removes every field required in env,
+// including the defaults, so that fields can be re-registered selectively
+// with RequireInEnvironment
+func (v *Validator) ClearEnvironmentRequirements(env string) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	canonicalize(&env, validEnvironments)
+	delete(v.requiredFields, env)
+}
+
+// SetIssuerFormatCheck enables or disables checking that the JWT issuer is
+// either a valid URL or a simple identifier without spaces
+func (v *Validator) SetIssuerFormatCheck(enabled bool) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.checkIssuerFormat = enabled
+}
+
+// SetAppNameCheck enables or disables warning about application names that
+// are not safe to use as logging or metrics labels, such as names with spaces
+func (v *Validator) SetAppNameCheck(enabled bool) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.checkAppName = enabled
+}
+
+// SetBindAllCheck enables or disables warning when the server binds to all
+// interfaces (0.0.0.0) in production without server.bind_all acknowledging it
+func (v *Validator) SetBindAllCheck(enabled bool) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.checkBindAll = enabled
+}
+
+// SetDialTimeout sets how long ValidateConnectionString waits for each
+// connection attempt, 5 seconds by default. A timeout of zero or less
+// restores the default.
+func (v *Validator) SetDialTimeout(timeout time.Duration) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if timeout <= 0 {
+		timeout = defaultDialTimeout
 	}
+	v.dialTimeout = timeout
+}
+
+// SetStrict enables or disables strict mode. In strict mode, warnings are
+// promoted to errors and fail validation.
+func (v *Validator) SetStrict(strict bool) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.strict = strict
 }
 
 // Validate validates the entire configuration
 func (v *Validator) Validate(config *Config) error {
-	v.errors = make([]string, 0)
+	_, err := v.validateWithWarnings(config, nil)
+	return err
+}
 
+// validateWithWarnings validates config and returns its warnings from the
+// same call, so concurrent validations cannot replace them. sources, as
+// reported by FieldSources, tells which fields were set explicitly; nil
+// means they are unknown.
+func (v *Validator) validateWithWarnings(config *Config, sources map[string]string) ([]string, error) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	v.errors = make([]FieldError, 0)
+	v.warnings = make([]FieldError, 0)
+	v.sources = sources
+	defer func() { v.sources = nil }()
+
+	// Enum-like fields are matched case-insensitively, without writing the
+	// canonical casing back to the caller's config
+	normalized := cloneConfig(config)
+	normalize(&normalized)
+	config = &normalized
+
+	v.validateTags("", reflect.ValueOf(*config))
 	v.validateServer(config.Server)
 	v.validateDatabase(config.Database)
 	v.validateRedis(config.Redis)
@@ -31,257 +180,656 @@ func (v *Validator) Validate(config *Config) error {
 	v.validateJWT(config.JWT)
 	v.validateEmail(config.Email)
 	v.validateApp(config.App)
+	v.validateRuntime(config.Runtime)
+	v.validateEnvironmentRequirements(config)
+	v.validateBindAll(config)
+	v.validateDeprecations(config)
+
+	if v.strict {
+		v.errors = append(v.errors, v.warnings...)
+	}
 
 	if len(v.errors) > 0 {
-		return &ValidationError{
-			Errors: v.errors,
-		}
+		return nil, newValidationError(v.errors)
 	}
 
-	return nil
+	return messages(v.warnings), nil
+}
+
+// Warnings returns the advisory, non-fatal findings of the most recent Validate call
+func (v *Validator) Warnings() []string {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	return messages(v.warnings)
+}
+
+// addError records a validation error for the field at path
+func (v *Validator) addError(path, message string) {
+	v.errors = append(v.errors, FieldError{Field: path, Message: message})
+}
+
+// addWarning records an advisory finding for the field at path
+func (v *Validator) addWarning(path, message string) {
+	v.warnings = append(v.warnings, FieldError{Field: path, Message: message})
+}
+
+// validatorPool holds reusable validators for high-frequency validation
+var validatorPool = sync.Pool{
+	New: func() interface{} {
+		return NewValidator()
+	},
+}
+
+// ValidatePooled validates a configuration using a validator taken from a
+// shared pool, avoiding a Validator allocation per call
+func ValidatePooled(config *Config) error {
+	v := validatorPool.Get().(*Validator)
+	defer validatorPool.Put(v)
+	return v.Validate(config)
+}
+
+// ValidateAll validates several named configurations, e.g. "dev", "staging"
+// and "prod", with a single validator. The result holds an error for each
+// configuration that failed, keyed by name; it is empty if all passed.
+func ValidateAll(configs map[string]*Config) map[string]error {
+	v := NewValidator()
+	failures := make(map[string]error)
+	for name, config := range configs {
+		if config == nil {
+			failures[name] = fmt.Errorf("no configuration")
+			continue
+		}
+		if err := v.Validate(config); err != nil {
+			failures[name] = err
+		}
+	}
+	return failures
 }
 
 // ValidationError represents validation errors
 type ValidationError struct {
 	Errors []string
+
+	// Fields holds the same findings as Errors, attributed to the dotted path
+	// of the offending field, e.g. "server.port"
+	Fields []FieldError
+}
+
+// FieldError is a single validation finding for one configuration field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// newValidationError builds a ValidationError from field findings
+func newValidationError(fieldErrors []FieldError) *ValidationError {
+	return &ValidationError{
+		Errors: messages(fieldErrors),
+		Fields: append([]FieldError(nil), fieldErrors...),
+	}
+}
+
+// messages returns the message of each finding
+func messages(fieldErrors []FieldError) []string {
+	msgs := make([]string, len(fieldErrors))
+	for i, fe := range fieldErrors {
+		msgs[i] = fe.Message
+	}
+	return msgs
 }
 
 func (e *ValidationError) Error() string {
 	return fmt.Sprintf("configuration validation failed: %s", strings.Join(e.Errors, "; "))
 }
 
+// MarshalJSON encodes the error as {"errors":[{"field":...,"message":...}]}.
+// Errors without field attribution are encoded with an empty field.
+func (e *ValidationError) MarshalJSON() ([]byte, error) {
+	fieldErrors := e.Fields
+	if len(fieldErrors) == 0 {
+		fieldErrors = make([]FieldError, len(e.Errors))
+		for i, msg := range e.Errors {
+			fieldErrors[i] = FieldError{Message: msg}
+		}
+	}
+	return json.Marshal(struct {
+		Errors []FieldError `json:"errors"`
+	}{Errors: fieldErrors})
+}
+
+// normalize rewrites enum-like fields to their canonical casing so that, e.g.,
+// "REQUIRE" and "Production" are accepted and stored as "require" and
+// "production". Loaders and the manager apply it to the configurations they
+// own; validators only normalize a copy.
+func normalize(config *Config) {
+	canonicalize(&config.Database.DatabaseConfigType, validDatabaseConfigTypes)
+	canonicalize(&config.Database.SSLMode, validSSLModes)
+	canonicalize(&config.Database.Environment, validEnvironments)
+	canonicalize(&config.Log.Level, validLogLevels)
+	canonicalize(&config.Log.Format, validLogFormats)
+	canonicalize(&config.App.Environment, validEnvironments)
+	canonicalize(&config.Email.Encryption, validSMTPEncryptions)
+}
+
+// canonicalize replaces *s with the accepted value it matches case-insensitively.
+// Already-canonical values are left untouched so validating a shared config
+// does not write to it.
+func canonicalize(s *string, valid []string) {
+	for _, option := range valid {
+		if *s != option && strings.EqualFold(*s, option) {
+			*s = option
+			return
+		}
+	}
+}
+
+// oneOf reports whether s matches one of the accepted values case-insensitively
+func oneOf(s string, valid []string) bool {
+	for _, option := range valid {
+		if strings.EqualFold(s, option) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString reports whether s is in values, matching case-sensitively
+func containsString(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
+
+// containsInt reports whether n is in values
+func containsInt(values []int, n int) bool {
+	for _, value := range values {
+		if value == n {
+			return true
+		}
+	}
+	return false
+}
+
+// validPortNumber reports whether port is a usable TCP port number
+func validPortNumber(port int) bool {
+	return port >= 1 && port <= 65535
+}
+
 // validateServer validates server configuration
 func (v *Validator) validateServer(config ServerConfig) {
 	if config.Port == "" {
-		v.errors = append(v.errors, "server port is required")
+		v.addError("server.port", "server port is required")
 	} else {
-		if _, err := strconv.Atoi(config.Port); err != nil {
-			v.errors = append(v.errors, "server port must be a valid integer")
+		if port, err := strconv.Atoi(config.Port); err != nil {
+			v.addError("server.port", "server port must be a valid integer")
+		} else if !validPortNumber(port) {
+			v.addError("server.port", "server port must be between 1 and 65535")
 		}
 	}
 
 	if config.Host == "" {
-		v.errors = append(v.errors, "server host is required")
+		v.addError("server.host", "server host is required")
 	}
 
 	if config.ReadTimeout <= 0 {
-		v.errors = append(v.errors, "server read timeout must be positive")
+		v.addError("server.read_timeout", "server read timeout must be positive")
 	}
 
 	if config.WriteTimeout <= 0 {
-		v.errors = append(v.errors, "server write timeout must be positive")
+		v.addError("server.write_timeout", "server write timeout must be positive")
 	}
 
 	if config.IdleTimeout <= 0 {
-		v.errors = append(v.errors, "server idle timeout must be positive")
+		v.addError("server.idle_timeout", "server idle timeout must be positive")
+	} else {
+		if config.ReadTimeout > config.IdleTimeout {
+			v.addWarning("server.read_timeout", fmt.Sprintf("server read timeout (%s) exceeds idle timeout (%s)", config.ReadTimeout, config.IdleTimeout))
+		}
+		if config.WriteTimeout > config.IdleTimeout {
+			v.addWarning("server.write_timeout", fmt.Sprintf("server write timeout (%s) exceeds idle timeout (%s)", config.WriteTimeout, config.IdleTimeout))
+		}
+	}
+
+	if config.MaxHeaderBytes < 0 {
+		v.addError("server.max_header_bytes", "server max header bytes must not be negative")
+	}
+
+	if config.TLSCertFile != "" && config.TLSKeyFile == "" {
+		v.addError("server.tls_key_file", "server TLS key file is required when a certificate file is set")
+	} else if config.TLSKeyFile != "" && config.TLSCertFile == "" {
+		v.addError("server.tls_cert_file", "server TLS certificate file is required when a key file is set")
+	}
+
+	if _, err := tlsVersion(config.MinTLSVersion); err != nil {
+		v.addError("server.min_tls_version", fmt.Sprintf("server min TLS version is invalid: %v", err))
 	}
 }
 
 // validateDatabase validates database configuration
 func (v *Validator) validateDatabase(config DatabaseConfig) {
 	// Validate database configuration type
-	if config.DatabaseConfigType != "" &&
-		config.DatabaseConfigType != "read_write" &&
-		config.DatabaseConfigType != "legacy" &&
-		config.DatabaseConfigType != "auto_detect" {
-		v.errors = append(v.errors, "database config type must be 'read_write', 'legacy', or 'auto_detect'")
+	if config.DatabaseConfigType != "" && !oneOf(config.DatabaseConfigType, validDatabaseConfigTypes) {
+		v.addError("database.config_type", "database config type must be 'read_write', 'legacy', or 'auto_detect'")
+	}
+
+	// Flag ambiguous configurations that populate both layouts without choosing one
+	if config.DatabaseConfigType == "" || strings.EqualFold(config.DatabaseConfigType, "auto_detect") {
+		legacySet := v.isExplicitlySet("database.host", config.Host)
+		readWriteSet := config.DBWriteHost != "" || config.DBReadHost != ""
+		if legacySet && readWriteSet {
+			v.addError("database.config_type", "both legacy database host and read/write database hosts are set; set database config type to 'legacy' or 'read_write' to choose one")
+		}
 	}
 
 	// Validate read/write database configuration
-	if config.DatabaseConfigType == "read_write" {
+	if strings.EqualFold(config.DatabaseConfigType, "read_write") {
 		v.validateReadWriteDatabase(config)
 	} else {
 		// Validate legacy database configuration
 		v.validateLegacyDatabase(config)
 	}
+
+	v.validateReadReplicas(config)
+}
+
+// isExplicitlySet reports whether the field at path, holding value, was set
+// rather than left at its default. Without sources to tell, as for Validate
+// or configurations installed with Manager.LoadConfig, a value other than the
+// default counts as set.
+func (v *Validator) isExplicitlySet(path, value string) bool {
+	if value == "" {
+		return false
+	}
+	if source, ok := v.sources[path]; ok && source != SourceMemory {
+		return source != SourceDefault
+	}
+	return value != bindingDefault(path)
+}
+
+// validateReadReplicas checks that every read replica is a host:port address
+func (v *Validator) validateReadReplicas(config DatabaseConfig) {
+	for i, replica := range config.ReadReplicas {
+		host, port, err := net.SplitHostPort(replica)
+		if err != nil || host == "" {
+			v.addError("database.read_replicas", fmt.Sprintf("read replica %d (%q) must be a host:port address", i+1, replica))
+			continue
+		}
+		if portNum, err := strconv.Atoi(port); err != nil || !validPortNumber(portNum) {
+			v.addError("database.read_replicas", fmt.Sprintf("read replica %d (%q) port must be between 1 and 65535", i+1, replica))
+		}
+	}
+
+	if len(config.ReadReplicas) > 0 && resolveDatabaseConfigType(config) != "read_write" {
+		v.addWarning("database.read_replicas", "read replicas are ignored unless read/write database configuration is in use")
+	}
 }
 
 // validateReadWriteDatabase validates read/write database configuration
 func (v *Validator) validateReadWriteDatabase(config DatabaseConfig) {
 	// Validate write database
 	if config.DBWriteHost == "" {
-		v.errors = append(v.errors, "write database host is required for read/write configuration")
+		v.addError("database.write_host", "write database host is required for read/write configuration")
 	}
 	if config.DBWritePort == "" {
-		v.errors = append(v.errors, "write database port is required")
+		v.addError("database.write_port", "write database port is required")
 	} else {
-		if _, err := strconv.Atoi(config.DBWritePort); err != nil {
-			v.errors = append(v.errors, "write database port must be a valid integer")
+		if port, err := strconv.Atoi(config.DBWritePort); err != nil {
+			v.addError("database.write_port", "write database port must be a valid integer")
+		} else if !validPortNumber(port) {
+			v.addError("database.write_port", "write database port must be between 1 and 65535")
 		}
 	}
 	if config.DBWriteUser == "" {
-		v.errors = append(v.errors, "write database user is required")
+		v.addError("database.write_user", "write database user is required")
 	}
 	if config.DBWriteName == "" {
-		v.errors = append(v.errors, "write database name is required")
+		v.addError("database.write_dbname", "write database name is required")
 	}
 
 	// Validate read database
 	if config.DBReadHost == "" {
-		v.errors = append(v.errors, "read database host is required for read/write configuration")
+		v.addError("database.read_host", "read database host is required for read/write configuration")
 	}
 	if config.DBReadPort == "" {
-		v.errors = append(v.errors, "read database port is required")
+		v.addError("database.read_port", "read database port is required")
 	} else {
-		if _, err := strconv.Atoi(config.DBReadPort); err != nil {
-			v.errors = append(v.errors, "read database port must be a valid integer")
+		if port, err := strconv.Atoi(config.DBReadPort); err != nil {
+			v.addError("database.read_port", "read database port must be a valid integer")
+		} else if !validPortNumber(port) {
+			v.addError("database.read_port", "read database port must be between 1 and 65535")
 		}
 	}
 	if config.DBReadUser == "" {
-		v.errors = append(v.errors, "read database user is required")
+		v.addError("database.read_user", "read database user is required")
 	}
 	if config.DBReadName == "" {
-		v.errors = append(v.errors, "read database name is required")
+		v.addError("database.read_dbname", "read database name is required")
 	}
 }
 
 // validateLegacyDatabase validates legacy database configuration
 func (v *Validator) validateLegacyDatabase(config DatabaseConfig) {
 	if config.Host == "" {
-		v.errors = append(v.errors, "database host is required")
+		v.addError("database.host", "database host is required")
 	}
 
 	if config.Port == "" {
-		v.errors = append(v.errors, "database port is required")
+		v.addError("database.port", "database port is required")
 	} else {
-		if _, err := strconv.Atoi(config.Port); err != nil {
-			v.errors = append(v.errors, "database port must be a valid integer")
+		if port, err := strconv.Atoi(config.Port); err != nil {
+			v.addError("database.port", "database port must be a valid integer")
+		} else if !validPortNumber(port) {
+			v.addError("database.port", "database port must be between 1 and 65535")
 		}
 	}
 
 	if config.User == "" {
-		v.errors = append(v.errors, "database user is required")
+		v.addError("database.user", "database user is required")
 	}
 
 	if config.DBName == "" {
-		v.errors = append(v.errors, "database name is required")
+		v.addError("database.dbname", "database name is required")
 	}
 
 	if config.MaxConns <= 0 {
-		v.errors = append(v.errors, "database max connections must be positive")
+		v.addError("database.max_conns", "database max connections must be positive")
 	}
 
-	// Validate SSL mode
-	validSSLModes := []string{"disable", "require", "verify-ca", "verify-full"}
-	valid := false
-	for _, mode := range validSSLModes {
-		if config.SSLMode == mode {
-			valid = true
-			break
-		}
-	}
-	if !valid {
-		v.errors = append(v.errors, fmt.Sprintf("database SSL mode must be one of: %s", strings.Join(validSSLModes, ", ")))
+	// Validate SSL mode against the modes of the database type
+	if sslModes := sslModesFor(config.DBType); !oneOf(config.SSLMode, sslModes) {
+		v.addError("database.sslmode", fmt.Sprintf("database SSL mode must be one of: %s", strings.Join(sslModes, ", ")))
 	}
 }
 
 // validateRedis validates Redis configuration
 func (v *Validator) validateRedis(config RedisConfig) {
 	if config.Host == "" {
-		v.errors = append(v.errors, "redis host is required")
+		v.addError("redis.host", "redis host is required")
 	}
 
 	if config.Port == "" {
-		v.errors = append(v.errors, "redis port is required")
+		v.addError("redis.port", "redis port is required")
 	} else {
-		if _, err := strconv.Atoi(config.Port); err != nil {
-			v.errors = append(v.errors, "redis port must be a valid integer")
+		if port, err := strconv.Atoi(config.Port); err != nil {
+			v.addError("redis.port", "redis port must be a valid integer")
+		} else if !validPortNumber(port) {
+			v.addError("redis.port", "redis port must be between 1 and 65535")
 		}
 	}
 
 	if config.DB < 0 || config.DB > 15 {
-		v.errors = append(v.errors, "redis database number must be between 0 and 15")
+		v.addError("redis.db", "redis database number must be between 0 and 15")
 	}
-}
 
-// validateLog validates logging configuration
-func (v *Validator) validateLog(config LogConfig) {
-	validLevels := []string{"debug", "info", "warn", "warning", "error", "fatal", "panic"}
-	valid := false
-	for _, level := range validLevels {
-		if strings.ToLower(config.Level) == level {
-			valid = true
-			break
+	if config.MinIdleConns < 0 {
+		v.addError("redis.min_idle_conns", "redis min idle connections must not be negative")
+	} else if config.PoolSize > 0 && config.MinIdleConns > config.PoolSize {
+		v.addError("redis.min_idle_conns", fmt.Sprintf("redis min idle connections (%d) must not exceed the pool size (%d)", config.MinIdleConns, config.PoolSize))
+	}
+
+	if config.URL != "" {
+		urlDB, ok, err := redisURLDB(config.URL)
+		if err != nil {
+			v.addError("redis.url", fmt.Sprintf("redis URL is invalid: %v", err))
+		} else if ok && config.DB != 0 && config.DB != urlDB {
+			v.addError("redis.db", fmt.Sprintf("redis database %d conflicts with database %d in redis URL; set only one of REDIS_DB or the URL path", config.DB, urlDB))
 		}
 	}
-	if !valid {
-		v.errors = append(v.errors, fmt.Sprintf("log level must be one of: %s", strings.Join(validLevels, ", ")))
+}
+
+// redisURLDB returns the database number selected by a redis:// URL path, if any
+func redisURLDB(rawURL string) (int, bool, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, false, err
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return 0, false, fmt.Errorf("unsupported scheme %q", u.Scheme)
 	}
 
-	validFormats := []string{"json", "text", "console"}
-	valid = false
-	for _, format := range validFormats {
-		if strings.ToLower(config.Format) == format {
-			valid = true
-			break
-		}
+	path := strings.Trim(u.Path, "/")
+	if path == "" {
+		return 0, false, nil
 	}
-	if !valid {
-		v.errors = append(v.errors, fmt.Sprintf("log format must be one of: %s", strings.Join(validFormats, ", ")))
+	db, err := strconv.Atoi(path)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid database %q", path)
+	}
+	return db, true, nil
+}
+
+// validateLog validates logging configuration
+func (v *Validator) validateLog(config LogConfig) {
+	if !oneOf(config.Level, validLogLevels) {
+		v.addError("log.level", fmt.Sprintf("log level must be one of: %s", strings.Join(validLogLevels, ", ")))
+	}
+
+	if !oneOf(config.Format, validLogFormats) {
+		v.addError("log.format", fmt.Sprintf("log format must be one of: %s", strings.Join(validLogFormats, ", ")))
 	}
 }
 
 // validateJWT validates JWT configuration
 func (v *Validator) validateJWT(config JWTConfig) {
-	if config.Secret == "" {
-		v.errors = append(v.errors, "JWT secret is required")
-	} else if len(config.Secret) < 32 {
-		v.errors = append(v.errors, "JWT secret must be at least 32 characters long")
+	if jwtUsesSecret(config) {
+		if config.Secret == "" {
+			v.addError("jwt.secret", "JWT secret is required")
+		} else if len(config.Secret) < 32 {
+			v.addError("jwt.secret", "JWT secret must be at least 32 characters long")
+		}
 	}
 
 	if config.Expiration <= 0 {
-		v.errors = append(v.errors, "JWT expiration must be positive")
+		v.addError("jwt.expiration", "JWT expiration must be positive")
 	}
 
 	if config.Issuer == "" {
-		v.errors = append(v.errors, "JWT issuer is required")
+		v.addError("jwt.issuer", "JWT issuer is required")
+	} else if v.checkIssuerFormat && !validIssuer(config.Issuer) {
+		v.addError("jwt.issuer", "JWT issuer must be a URL (e.g., https://auth.example.com) or an identifier without spaces")
+	}
+
+	v.validateJWTKeys(config)
+}
+
+// validateJWTKeys checks the signing algorithm and, for RS* and ES*
+// algorithms, that the key files parse and suit the algorithm
+func (v *Validator) validateJWTKeys(config JWTConfig) {
+	algorithm := jwtAlgorithm(config)
+	if !oneOf(algorithm, jwtAlgorithms) {
+		v.addError("jwt.algorithm", fmt.Sprintf("JWT algorithm must be one of: %s", strings.Join(jwtAlgorithms, ", ")))
+		return
+	}
+	if strings.HasPrefix(algorithm, "HS") {
+		return
+	}
+
+	if config.PrivateKeyPath == "" && config.PublicKeyPath == "" {
+		v.addError("jwt.private_key_path", fmt.Sprintf("JWT private or public key path is required for %s", algorithm))
+		return
+	}
+
+	var private crypto.PrivateKey
+	if config.PrivateKeyPath != "" {
+		key, err := readJWTPrivateKey(config.PrivateKeyPath, algorithm)
+		if err != nil {
+			v.addError("jwt.private_key_path", err.Error())
+		}
+		private = key
+	}
+
+	if config.PublicKeyPath != "" {
+		public, err := readJWTPublicKey(config.PublicKeyPath, algorithm)
+		if err != nil {
+			v.addError("jwt.public_key_path", err.Error())
+		} else if private != nil && !jwtKeysMatch(private, public) {
+			v.addError("jwt.public_key_path", "JWT public key does not match the private key")
+		}
+	}
+}
+
+// validIssuer reports whether issuer is an absolute URL or a whitespace-free identifier
+func validIssuer(issuer string) bool {
+	if strings.IndexFunc(issuer, unicode.IsSpace) >= 0 {
+		return false
+	}
+	if strings.Contains(issuer, "://") {
+		u, err := url.Parse(issuer)
+		return err == nil && u.Scheme != "" && u.Host != ""
 	}
+	return true
 }
 
 // validateEmail validates email configuration
 func (v *Validator) validateEmail(config EmailConfig) {
 	if config.Host != "" {
-		if config.Port <= 0 || config.Port > 65535 {
-			v.errors = append(v.errors, "email port must be between 1 and 65535")
+		if !validPortNumber(config.Port) {
+			v.addError("email.port", "email port must be between 1 and 65535")
+		} else if !containsInt(commonSMTPPorts, config.Port) {
+			v.addWarning("email.port", fmt.Sprintf("email port %d is not a common SMTP port (25, 465, 587, 2525)", config.Port))
 		}
 
 		if config.Username == "" {
-			v.errors = append(v.errors, "email username is required when email host is provided")
+			v.addError("email.username", "email username is required when email host is provided")
 		}
 
 		if config.From == "" {
-			v.errors = append(v.errors, "email from address is required when email host is provided")
+			v.addError("email.from", "email from address is required when email host is provided")
 		}
 	}
+
+	if config.Encryption != "" && !oneOf(config.Encryption, validSMTPEncryptions) {
+		v.addError("email.encryption", "email encryption must be 'none', 'starttls' or 'tls'")
+	}
+
+	if config.From != "" && len(config.AllowedFromDomains) > 0 {
+		v.validateFromDomain(config)
+	}
+}
+
+// validateFromDomain checks that the domain of the sender address is allowlisted
+func (v *Validator) validateFromDomain(config EmailConfig) {
+	address, err := mail.ParseAddress(config.From)
+	if err != nil {
+		v.addError("email.from", fmt.Sprintf("email from address %q is invalid: %v", config.From, err))
+		return
+	}
+
+	domain := address.Address[strings.LastIndex(address.Address, "@")+1:]
+	if !oneOf(domain, config.AllowedFromDomains) {
+		v.addError("email.from", fmt.Sprintf("email from domain %q is not allowed; allowed domains: %s", domain, strings.Join(config.AllowedFromDomains, ", ")))
+	}
 }
 
 // validateApp validates application configuration
 func (v *Validator) validateApp(config AppConfig) {
 	if config.Name == "" {
-		v.errors = append(v.errors, "application name is required")
+		v.addError("app.name", "application name is required")
+	}
+
+	if !oneOf(config.Environment, validEnvironments) {
+		v.addError("app.environment", fmt.Sprintf("application environment must be one of: %s", strings.Join(validEnvironments, ", ")))
+	}
+
+	if v.checkAppName && config.Name != "" && !metricSafeNamePattern.MatchString(config.Name) {
+		v.addWarning("app.name", fmt.Sprintf("application name %q is not a safe metrics label; use letters, digits and underscores, e.g. %q", config.Name, MetricSafeName(config.Name)))
+	}
+}
+
+// validateRuntime validates Go runtime configuration
+func (v *Validator) validateRuntime(config RuntimeConfig) {
+	if config.MemoryLimit != "" {
+		if limit, err := parseByteSize(config.MemoryLimit); err != nil {
+			v.addError("runtime.memory_limit", fmt.Sprintf("runtime memory limit is invalid: %v", err))
+		} else if limit <= 0 {
+			v.addError("runtime.memory_limit", "runtime memory limit must be positive")
+		}
+	}
+}
+
+// MetricSafeName converts name into a label-safe identifier: lowercase
+// letters, digits and single underscores, not starting with a digit, e.g.
+// "My App (EU)" becomes "my_app_eu"
+func MetricSafeName(name string) string {
+	var b strings.Builder
+	pendingUnderscore := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if pendingUnderscore && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			pendingUnderscore = false
+			b.WriteRune(r)
+			continue
+		}
+		pendingUnderscore = true
+	}
+
+	slug := b.String()
+	if slug == "" || (slug[0] >= '0' && slug[0] <= '9') {
+		slug = "_" + slug
 	}
+	return slug
+}
 
-	validEnvironments := []string{"development", "staging", "production", "test"}
-	valid := false
-	for _, env := range validEnvironments {
-		if strings.ToLower(config.Environment) == env {
-			valid = true
-			break
+// validateEnvironmentRequirements enforces the fields required in the
+// configured environment. A JWT secret left at a well-known placeholder is an
+// error in production and a warning elsewhere.
+func (v *Validator) validateEnvironmentRequirements(config *Config) {
+	env := config.App.Environment
+	for _, path := range v.requiredFields[env] {
+		field, ok := lookupField(config, path)
+		if !ok {
+			v.addError(path, fmt.Sprintf("unknown field %s required in %s", path, env))
+			continue
+		}
+		if field.IsZero() {
+			v.addError(path, fmt.Sprintf("%s is required in %s", path, env))
 		}
 	}
-	if !valid {
-		v.errors = append(v.errors, fmt.Sprintf("application environment must be one of: %s", strings.Join(validEnvironments, ", ")))
+
+	if jwtUsesSecret(config.JWT) && isPlaceholderJWTSecret(config.JWT.Secret) {
+		if env == "production" {
+			v.addError("jwt.secret", "JWT secret must be changed from its default value in production")
+		} else {
+			v.addWarning("jwt.secret", "JWT secret is a well-known placeholder value; set JWT_SECRET to a random secret")
+		}
 	}
+}
 
-	if config.Version == "" {
-		v.errors = append(v.errors, "application version is required")
+// validateBindAll warns about a production server listening on all
+// interfaces unless server.bind_all acknowledges it
+func (v *Validator) validateBindAll(config *Config) {
+	if !v.checkBindAll || config.App.Environment != "production" || config.Server.BindAll {
+		return
 	}
+	if config.Server.Host == "0.0.0.0" {
+		v.addWarning("server.host", "server binds to all interfaces (0.0.0.0) in production; set a specific host or SERVER_BIND_ALL=true to acknowledge")
+	}
+}
+
+// documentedJWTSecret is the placeholder JWT secret used throughout the
+// README and the examples directory
+const documentedJWTSecret = "your-super-secret-jwt-key-that-is-at-least-32-characters-long"
+
+// isPlaceholderJWTSecret reports whether secret is the built-in default or
+// one of the placeholders shipped in examples and documentation
+func isPlaceholderJWTSecret(secret string) bool {
+	return secret == bindingDefault("jwt.secret") || secret == exampleJWTSecret || secret == documentedJWTSecret
 }
 
-// ValidateConnectionString validates if a connection string is reachable
+// ValidateConnectionString validates if a connection string is reachable,
+// waiting at most the dial timeout set with SetDialTimeout
 func (v *Validator) ValidateConnectionString(host, port string) error {
+	v.mutex.Lock()
+	timeout := v.dialTimeout
+	v.mutex.Unlock()
+
 	address := net.JoinHostPort(host, port)
-	conn, err := net.DialTimeout("tcp", address, 5*time.Second)
+	conn, err := net.DialTimeout("tcp", address, timeout)
 	if err != nil {
 		return fmt.Errorf("cannot connect to %s: %w", address, err)
 	}
@@ -289,6 +837,27 @@ func (v *Validator) ValidateConnectionString(host, port string) error {
 	return nil
 }
 
+// ValidateConnectionStringWithRetry is like ValidateConnectionString but
+// makes up to attempts connection attempts, waiting delay between them, for
+// dependencies that may still be starting. Each attempt is bounded by the
+// dial timeout.
+func (v *Validator) ValidateConnectionStringWithRetry(host, port string, attempts int, delay time.Duration) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := v.ValidateConnectionString(host, port)
+		if err == nil {
+			return nil
+		}
+		if attempt >= attempts {
+			return fmt.Errorf("connection check failed after %d attempts: %w", attempt, err)
+		}
+		time.Sleep(delay)
+	}
+}
+
 // ValidatePort validates if a port is available
 func (v *Validator) ValidatePort(port string) error {
 	portNum, err := strconv.Atoi(port)
@@ -296,9 +865,19 @@ func (v *Validator) ValidatePort(port string) error {
 		return fmt.Errorf("invalid port number: %s", port)
 	}
 
-	if portNum < 1 || portNum > 65535 {
+	if !validPortNumber(portNum) {
 		return fmt.Errorf("port number must be between 1 and 65535")
 	}
 
 	return nil
 }
+
+// ValidatePortAvailable validates that host:port can currently be bound
+func (v *Validator) ValidatePortAvailable(host, port string) error {
+	address := net.JoinHostPort(host, port)
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("cannot bind %s: %w", address, err)
+	}
+	return listener.Close()
+}
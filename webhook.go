@@ -0,0 +1,125 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Webhook delivery defaults
+const (
+	defaultWebhookTimeout    = 10 * time.Second
+	defaultWebhookAttempts   = 3
+	defaultWebhookRetryDelay = time.Second
+	webhookContentType       = "application/json"
+)
+
+// WebhookPayload is the JSON body POSTed by a WebhookWatcher
+type WebhookPayload struct {
+	Timestamp time.Time     `json:"timestamp"`
+	Changes   []FieldChange `json:"changes"`
+}
+
+// WebhookWatcher is a ConfigWatcher that POSTs a redacted diff of every
+// configuration change to a webhook URL, e.g. a Slack incoming webhook
+type WebhookWatcher struct {
+	url        string
+	client     *http.Client
+	timeout    time.Duration
+	attempts   int
+	retryDelay time.Duration
+	onError    func(error)
+}
+
+// NewWebhookWatcher creates a watcher that posts changes to url. A nil client
+// uses http.DefaultClient. Each attempt is bounded by a 10 second timeout and
+// failed deliveries are retried up to 3 times.
+func NewWebhookWatcher(url string, client *http.Client) *WebhookWatcher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &WebhookWatcher{
+		url:        url,
+		client:     client,
+		timeout:    defaultWebhookTimeout,
+		attempts:   defaultWebhookAttempts,
+		retryDelay: defaultWebhookRetryDelay,
+	}
+}
+
+// SetTimeout sets the timeout applied to each delivery attempt
+func (w *WebhookWatcher) SetTimeout(timeout time.Duration) {
+	w.timeout = timeout
+}
+
+// SetRetry sets the number of delivery attempts and the delay between them
+func (w *WebhookWatcher) SetRetry(attempts int, delay time.Duration) {
+	if attempts < 1 {
+		attempts = 1
+	}
+	w.attempts = attempts
+	w.retryDelay = delay
+}
+
+// SetErrorHandler sets a function called when a change could not be delivered
+// after all attempts. Delivery errors are otherwise dropped.
+func (w *WebhookWatcher) SetErrorHandler(handler func(error)) {
+	w.onError = handler
+}
+
+// OnConfigChanged implements ConfigWatcher
+func (w *WebhookWatcher) OnConfigChanged(oldConfig, newConfig *Config) {
+	changes := RedactChanges(Diff(oldConfig, newConfig))
+	if len(changes) == 0 {
+		return
+	}
+
+	if err := w.send(WebhookPayload{Timestamp: time.Now().UTC(), Changes: changes}); err != nil && w.onError != nil {
+		w.onError(err)
+	}
+}
+
+// send delivers payload, retrying failed attempts
+func (w *WebhookWatcher) send(payload WebhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = w.post(body)
+		if err == nil {
+			return nil
+		}
+		if attempt >= w.attempts {
+			return fmt.Errorf("webhook delivery failed after %d attempts: %w", attempt, err)
+		}
+		time.Sleep(w.retryDelay)
+	}
+}
+
+// post makes a single delivery attempt
+func (w *WebhookWatcher) post(body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", webhookContentType)
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %s", resp.Status)
+	}
+	return nil
+}